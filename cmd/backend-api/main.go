@@ -1,6 +1,7 @@
 package main
 
 import (
+	"binance-trade-bot-go/internal/clock"
 	"binance-trade-bot-go/internal/config"
 	"binance-trade-bot-go/internal/database"
 	"binance-trade-bot-go/internal/logger"
@@ -19,23 +20,26 @@ import (
 type APIHandler struct {
 	log        *zap.Logger
 	db         *gorm.DB
+	clock      clock.Clock
 	traderURLs []string
 }
 
 // NewAPIHandler creates a new APIHandler.
-func NewAPIHandler(log *zap.Logger, db *gorm.DB, traderURLs []string) *APIHandler {
-	return &APIHandler{log: log, db: db, traderURLs: traderURLs}
+// The clock decides which trades fall in the last 24 hours of the statistics.
+func NewAPIHandler(log *zap.Logger, db *gorm.DB, clk clock.Clock, traderURLs []string) *APIHandler {
+	return &APIHandler{log: log, db: db, clock: clk, traderURLs: traderURLs}
 }
 
 // TraderStatus represents the status of a single trader instance.
 type TraderStatus struct {
-	UUID      string `json:"uuid"`
-	Name      string `json:"name"`
-	Strategy  string `json:"strategy"`
-	StartTime string `json:"start_time"`
-	Uptime    string `json:"uptime"`
-	IsHealthy bool   `json:"is_healthy"`
-	Error     string `json:"error,omitempty"`
+	UUID         string `json:"uuid"`
+	Name         string `json:"name"`
+	Strategy     string `json:"strategy"`
+	StartTime    string `json:"start_time"`
+	Uptime       string `json:"uptime"`
+	TimeOffsetMs int64  `json:"time_offset_ms"`
+	IsHealthy    bool   `json:"is_healthy"`
+	Error        string `json:"error,omitempty"`
 }
 
 // TradersHandler fetches the status of all configured traders.
@@ -128,7 +132,7 @@ func (h *APIHandler) StatisticsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	now := time.Now()
+	now := h.clock.Now()
 	since24h := now.Add(-24 * time.Hour)
 
 	stats24h := StatsDetail{}
@@ -185,23 +189,18 @@ func main() {
 	}
 	defer log.Sync()
 
-	// Connect to the database
+	// Connect to the database. Pending migrations are applied without touching
+	// existing data, so the dashboard can safely share the trader's database.
 	db, err := database.NewDatabase(&cfg)
 	if err != nil {
 		log.Fatal("Failed to connect to database", zap.Error(err))
 	}
 
-	// Auto-migrate the schema
-	log.Info("Running database migrations...")
-	if err := db.AutoMigrate(&models.Coin{}, &models.Pair{}, &models.Trade{}); err != nil {
-		log.Fatal("Failed to migrate database", zap.Error(err))
-	}
-
 	// Setup HTTP server
 	mux := http.NewServeMux()
 
 	// Create a handler that has access to the logger and db
-	apiHandler := NewAPIHandler(log, db, cfg.Server.TraderURLs)
+	apiHandler := NewAPIHandler(log, db, clock.Real(), cfg.Server.TraderURLs)
 
 	// API endpoints
 	mux.HandleFunc("/api/trades", apiHandler.TradesHandler)
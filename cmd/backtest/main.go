@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"binance-trade-bot-go/internal/backtest"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/logger"
+	"go.uber.org/zap"
+)
+
+func main() {
+	configDir := flag.String("config", "./configs", "directory of config.yml")
+	dataDir := flag.String("data", "", "directory of 1m kline CSV files from data.binance.vision, e.g. BTCUSDT-1m-2024-01.csv")
+	strategy := flag.String("strategy", "", "strategy to backtest instead of trading.strategy")
+	slippage := flag.Float64("slippage", 0.0005, "fraction of the price every market order loses")
+	from := flag.String("from", "", "start of the backtest, YYYY-MM-DD or RFC 3339 (default: start of the data)")
+	to := flag.String("to", "", "end of the backtest, YYYY-MM-DD or RFC 3339 (default: end of the data)")
+	balances := flag.String("balances", "", "starting balances instead of trading.paper_balances, e.g. BTC=0.01,USDT=100")
+	logLevel := flag.String("log-level", "warn", "log level of the trading engine")
+	flag.Parse()
+
+	if *dataDir == "" {
+		fmt.Fprintln(os.Stderr, "-data is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig(*configDir)
+	if err != nil {
+		// We can't use the logger here because it's not initialized yet.
+		panic(fmt.Sprintf("could not load config: %v", err))
+	}
+	if *strategy != "" {
+		cfg.Trading.Strategy = *strategy
+	}
+
+	log, err := logger.NewLogger(*logLevel, cfg.Logger.Format)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Sync()
+
+	opts := backtest.Options{Slippage: *slippage}
+	if opts.Start, err = backtest.ParseTime(*from); err != nil {
+		log.Fatal("Invalid -from", zap.Error(err))
+	}
+	if opts.End, err = backtest.ParseTime(*to); err != nil {
+		log.Fatal("Invalid -to", zap.Error(err))
+	}
+	if *balances != "" {
+		if opts.Balances, err = backtest.ParseBalances(*balances); err != nil {
+			log.Fatal("Invalid -balances", zap.Error(err))
+		}
+	}
+
+	data, err := backtest.LoadDataset(*dataDir)
+	if err != nil {
+		log.Fatal("Failed to load klines", zap.Error(err))
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	report, err := backtest.Run(ctx, cfg, data, opts, log)
+	if err != nil {
+		log.Fatal("Backtest failed", zap.Error(err))
+	}
+	report.Print(os.Stdout)
+}
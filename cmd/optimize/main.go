@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"binance-trade-bot-go/internal/backtest"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/logger"
+	"go.uber.org/zap"
+)
+
+func main() {
+	configDir := flag.String("config", "./configs", "directory of config.yml")
+	dataDir := flag.String("data", "", "directory of 1m kline CSV files from data.binance.vision, e.g. BTCUSDT-1m-2024-01.csv")
+	strategy := flag.String("strategy", "", "strategy to optimize instead of trading.strategy")
+	slippage := flag.Float64("slippage", 0.0005, "fraction of the price every market order loses")
+	from := flag.String("from", "", "start of the backtests, YYYY-MM-DD or RFC 3339 (default: start of the data)")
+	to := flag.String("to", "", "end of the backtests, YYYY-MM-DD or RFC 3339 (default: end of the data)")
+	balances := flag.String("balances", "", "starting balances instead of trading.paper_balances, e.g. BTC=0.01,USDT=100")
+	margins := flag.String("margins", "", "scout_margin values to try, e.g. 0.2,0.5,1 (default: trading.scout_margin)")
+	ticks := flag.String("ticks", "", "tick_interval values in seconds to try, e.g. 60,300 (default: trading.tick_interval)")
+	fees := flag.String("fees", "", "fee_rate values to try, e.g. 0.001,0.00075 (default: trading.fee_rate)")
+	coins := flag.String("coins", "", "coin sets to try, separated by semicolons, e.g. BTC,ETH;BTC,ETH,BNB (default: trading.trade_pairs)")
+	subsetSize := flag.Int("subset-size", 0, "try every subset of this many coins of trading.trade_pairs instead of -coins")
+	random := flag.Int("random", 0, "run this many random combinations instead of the full grid")
+	seed := flag.Int64("seed", 0, "seed of -random (default: the current time)")
+	workers := flag.Int("workers", runtime.NumCPU(), "backtests to run in parallel")
+	rank := flag.String("rank", backtest.RankByReturn, `rank results by "return" or "drawdown"`)
+	out := flag.String("out", "", "write the ranked results to this .csv or .json file")
+	top := flag.Int("top", 10, "results to print, 0 for all")
+	logLevel := flag.String("log-level", "error", "log level of the trading engine")
+	flag.Parse()
+
+	if *dataDir == "" {
+		fmt.Fprintln(os.Stderr, "-data is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig(*configDir)
+	if err != nil {
+		// We can't use the logger here because it's not initialized yet.
+		panic(fmt.Sprintf("could not load config: %v", err))
+	}
+	if *strategy != "" {
+		cfg.Trading.Strategy = *strategy
+	}
+
+	log, err := logger.NewLogger(*logLevel, cfg.Logger.Format)
+	if err != nil {
+		panic(err)
+	}
+	defer log.Sync()
+
+	if *rank != backtest.RankByReturn && *rank != backtest.RankByDrawdown {
+		log.Fatal("Invalid -rank, expected return or drawdown", zap.String("rank", *rank))
+	}
+	format := strings.TrimPrefix(filepath.Ext(*out), ".")
+	if *out != "" && format != "csv" && format != "json" {
+		log.Fatal("Invalid -out, expected a .csv or .json file", zap.String("out", *out))
+	}
+
+	opts := backtest.Options{Slippage: *slippage}
+	if opts.Start, err = backtest.ParseTime(*from); err != nil {
+		log.Fatal("Invalid -from", zap.Error(err))
+	}
+	if opts.End, err = backtest.ParseTime(*to); err != nil {
+		log.Fatal("Invalid -to", zap.Error(err))
+	}
+	if *balances != "" {
+		if opts.Balances, err = backtest.ParseBalances(*balances); err != nil {
+			log.Fatal("Invalid -balances", zap.Error(err))
+		}
+	}
+
+	var grid backtest.Grid
+	if grid.ScoutMargins, err = parseList(*margins, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }); err != nil {
+		log.Fatal("Invalid -margins", zap.Error(err))
+	}
+	if grid.TickIntervals, err = parseList(*ticks, strconv.Atoi); err != nil {
+		log.Fatal("Invalid -ticks", zap.Error(err))
+	}
+	if grid.FeeRates, err = parseList(*fees, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) }); err != nil {
+		log.Fatal("Invalid -fees", zap.Error(err))
+	}
+	if *subsetSize > 0 {
+		grid.CoinSets = backtest.CoinSubsets(cfg.Trading.TradePairs, *subsetSize)
+		if len(grid.CoinSets) == 0 {
+			log.Fatal("Invalid -subset-size, trading.trade_pairs has fewer coins", zap.Int("subset_size", *subsetSize))
+		}
+	} else if *coins != "" {
+		for _, set := range strings.Split(*coins, ";") {
+			grid.CoinSets = append(grid.CoinSets, strings.Split(strings.ToUpper(set), ","))
+		}
+	}
+
+	trials := grid.Trials(cfg.Trading)
+	if *random > 0 {
+		if *seed == 0 {
+			*seed = time.Now().UnixNano()
+		}
+		trials = grid.Sample(cfg.Trading, *random, rand.New(rand.NewSource(*seed)))
+	}
+
+	data, err := backtest.LoadDataset(*dataDir)
+	if err != nil {
+		log.Fatal("Failed to load klines", zap.Error(err))
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	fmt.Fprintf(os.Stderr, "Running %d backtests on %d workers...\n", len(trials), *workers)
+	results := backtest.Sweep(ctx, cfg, data, opts, trials, *workers, log)
+	backtest.Rank(results, *rank)
+
+	if *out != "" {
+		if err := writeResults(*out, format, results); err != nil {
+			log.Fatal("Failed to write results", zap.Error(err))
+		}
+	}
+	backtest.PrintResults(os.Stdout, results, *top)
+}
+
+// parseList parses a comma separated list. An empty value is an empty list.
+func parseList[T any](value string, parse func(string) (T, error)) ([]T, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var values []T
+	for _, s := range strings.Split(value, ",") {
+		v, err := parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// writeResults writes the results to path as CSV or JSON.
+func writeResults(path, format string, results []backtest.Result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if format == "json" {
+		err = backtest.WriteJSON(f, results)
+	} else {
+		err = backtest.WriteCSV(f, results)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -17,6 +18,15 @@ import (
 )
 
 func main() {
+	listStrategies := flag.Bool("list-strategies", false, "print the available strategies and exit")
+	flag.Parse()
+	if *listStrategies {
+		for _, name := range trader.StrategyNames() {
+			fmt.Println(name)
+		}
+		return
+	}
+
 	// Load application configuration
 	cfg, err := config.LoadConfig("./configs")
 	if err != nil {
@@ -39,13 +49,6 @@ func main() {
 	}
 	log.Info("Database connection successful and schema migrated.")
 
-	// Initialize Binance REST client
-	restClient := binance.NewRestClient(&cfg.Binance, log)
-	if _, err := restClient.GetServerTime(); err != nil {
-		log.Fatal("Failed to connect to Binance API", zap.Error(err))
-	}
-	log.Info("Successfully connected to Binance API.")
-
 	// Setup context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
@@ -56,23 +59,43 @@ func main() {
 		cancel()
 	}()
 
+	// Initialize Binance REST client
+	restClient := binance.NewRestClient(&cfg.Binance, log)
+	// Measuring the clock offset doubles as a connectivity check
+	if err := restClient.SyncTime(ctx); err != nil {
+		log.Fatal("Failed to connect to Binance API", zap.Error(err))
+	}
+	log.Info("Successfully connected to Binance API.", zap.Duration("time_offset", restClient.TimeOffset()))
+
+	// In dry-run mode orders are filled by a paper exchange that reads live prices
+	// from Binance but never sends anything that would touch real funds.
+	var exchange binance.RestClientInterface = restClient
+	if cfg.Trading.DryRun {
+		exchange = binance.NewPaperClient(restClient, cfg.Trading.FeeRate, cfg.Trading.PaperBalances, log)
+	}
+
+	// Keep compensating for local clock drift on signed requests
+	go restClient.RunTimeSync(ctx, time.Duration(cfg.Binance.TimeSyncInterval)*time.Second)
+
 	// --- Strategy and Engine Setup ---
-	var selectedStrategy trader.Strategy
-	strategyName := cfg.Trading.Strategy
-
-	switch strategyName {
-	case "Default":
-		selectedStrategy = &trader.DefaultStrategy{}
-	case "MultipleCoins":
-		selectedStrategy = &trader.MultipleCoinsStrategy{}
-	default:
-		log.Fatal("Invalid strategy specified in config", zap.String("strategy", strategyName))
+	// Strategies register themselves with the trader package, see trader.RegisterStrategy.
+	selectedStrategy, err := trader.NewStrategy(cfg.Trading.Strategy, cfg.Trading.StrategyParams)
+	if err != nil {
+		log.Fatal("Invalid strategy specified in config", zap.Error(err))
 	}
 
 	log.Info("Using strategy", zap.String("strategy", selectedStrategy.Name()))
 
 	// Initialize and run the trading engine with the selected strategy
-	tradeEngine := trader.NewEngine(log, &cfg, restClient, db, selectedStrategy)
+	tradeEngine := trader.NewEngine(log, &cfg, exchange, db, selectedStrategy)
+
+	// Stream market data over WebSocket, falling back to REST while the stream is stale
+	if cfg.Binance.StreamEnabled {
+		stream := binance.NewStreamClient(&cfg.Binance, log)
+		stream.SetSnapshotSource(restClient)
+		go stream.Run(ctx)
+		tradeEngine.SetPriceSource(trader.NewFallbackPriceSource(stream, restClient, log))
+	}
 
 	// Start the API server
 	apiServer := trader.NewAPIServer(tradeEngine, log)
@@ -0,0 +1,166 @@
+// Package backtest replays historical klines through a trading strategy.
+//
+// The strategy runs inside the regular trader.Engine against a Market that
+// serves the klines at the time of a manual clock and a binance.PaperClient
+// that fills its orders with fees, so a backtest exercises the same code as a
+// live trader.
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/clock"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/database"
+	"binance-trade-bot-go/internal/models"
+	"binance-trade-bot-go/internal/trader"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Options control a backtest run.
+type Options struct {
+	Start    time.Time          // Zero for the start of the dataset
+	End      time.Time          // Zero for the end of the dataset
+	Slippage float64            // Fraction of the price every market order loses, e.g. 0.0005
+	Balances map[string]float64 // Starting balances, trading.paper_balances when nil
+}
+
+// Run backtests the strategy configured in cfg over data and reports how it
+// did. cfg is the regular trader config: the strategy and its parameters, the
+// bridge, trade_pairs, fee_rate and scout_margin apply as they would live. The
+// engine, the strategy and the paper exchange run on a clock.Manual that
+// advances by tick_interval between scouts, at least one kline. Each run keeps
+// its own in-memory database, so runs can be made concurrently over the same
+// dataset.
+func Run(ctx context.Context, cfg config.Config, data *Dataset, opts Options, log *zap.Logger) (*Report, error) {
+	start, end := data.Start(), data.End()
+	if !opts.Start.IsZero() && opts.Start.After(start) {
+		start = opts.Start
+	}
+	if !opts.End.IsZero() && opts.End.Before(end) {
+		end = opts.End
+	}
+	if !start.Before(end) {
+		return nil, fmt.Errorf("nothing to backtest between %s and %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+	step := time.Duration(cfg.Trading.TickInterval) * time.Second
+	if step < KlineInterval {
+		step = KlineInterval
+	}
+
+	// Every order is simulated, so trades are recorded like in dry-run mode.
+	cfg.Trading.DryRun = true
+	balances := opts.Balances
+	if balances == nil {
+		balances = cfg.Trading.PaperBalances
+	}
+
+	clk := clock.NewManual(start)
+	db, err := openDatabase(&cfg, clk)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}()
+
+	strategy, err := trader.NewStrategy(cfg.Trading.Strategy, cfg.Trading.StrategyParams)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := append([]string{cfg.Trading.Bridge}, cfg.Trading.TradePairs...)
+	market := NewMarket(data, clk, assets, opts.Slippage)
+	exchange := binance.NewPaperClient(market, cfg.Trading.FeeRate, balances, log)
+	exchange.SetClock(clk)
+
+	engine := trader.NewEngine(log, &cfg, exchange, db, strategy)
+	engine.SetClock(clk)
+	strategyCtx, err := engine.Setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Strategy: strategy.Name(), Start: start, End: end}
+	initial := exchange.Balances()
+	report.InitialValue = value(market, cfg.Trading.Bridge, initial)
+
+	peak := report.InitialValue
+	for t := start; !t.After(end); t = t.Add(step) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		clk.Set(t)
+		engine.Tick(strategyCtx)
+		report.Ticks++
+
+		v := value(market, cfg.Trading.Bridge, exchange.Balances())
+		if v.GreaterThan(peak) {
+			peak = v
+		}
+		if peak.IsPositive() {
+			if dd := peak.Sub(v).Div(peak).InexactFloat64(); dd > report.MaxDrawdown {
+				report.MaxDrawdown = dd
+			}
+		}
+		report.FinalValue = v
+	}
+	report.HODLValue = value(market, cfg.Trading.Bridge, initial)
+
+	if err := db.Model(&models.Trade{}).Count(&report.Trades).Error; err != nil {
+		return nil, fmt.Errorf("could not count trades: %w", err)
+	}
+	return report, nil
+}
+
+// openDatabase creates an in-memory database with the schema and coins of cfg.
+// Records are timestamped with clk.
+func openDatabase(cfg *config.Config, clk clock.Clock) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger:  logger.Default.LogMode(logger.Silent),
+		NowFunc: clk.Now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backtest database: %w", err)
+	}
+	// Every connection to :memory: is a database of its own, keep to one.
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := database.Migrate(db); err != nil {
+		return nil, err
+	}
+	if err := database.SeedCoins(db, cfg); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// value returns what balances are worth in the bridge at the market's prices.
+// Assets without a price against the bridge are left out.
+func value(market *Market, bridge string, balances map[string]decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
+	for asset, qty := range balances {
+		if strings.EqualFold(asset, bridge) {
+			total = total.Add(qty)
+			continue
+		}
+		if price, ok := market.Price(asset + strings.ToUpper(bridge)); ok {
+			total = total.Add(qty.Mul(price))
+		}
+	}
+	return total
+}
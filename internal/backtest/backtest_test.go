@@ -0,0 +1,74 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"binance-trade-bot-go/internal/config"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// minuteKlines returns one kline per minute from testStart closing at prices.
+func minuteKlines(prices ...float64) []Kline {
+	klines := make([]Kline, len(prices))
+	for i, p := range prices {
+		klines[i] = Kline{OpenTime: testStart.Add(time.Duration(i) * time.Minute), Close: decimal.NewFromFloat(p)}
+	}
+	return klines
+}
+
+func testConfig() config.Config {
+	return config.Config{Trading: config.Trading{
+		Bridge:        "USDT",
+		TradePairs:    []string{"BTC", "ETH"},
+		StartingCoin:  "BTC",
+		FeeRate:       0.001,
+		ProfitModel:   "bid_ask",
+		PaperBalances: map[string]float64{"BTC": 1},
+		TickInterval:  60,
+		Strategy:      "Default",
+	}}
+}
+
+func TestRun_JumpsWhenTheRatioMoves(t *testing.T) {
+	// ETH drops 20% against BTC for a few minutes and then recovers.
+	data := NewDataset(map[string][]Kline{
+		"BTCUSDT": minuteKlines(100, 100, 100, 100, 100, 100, 100, 100),
+		"ETHUSDT": minuteKlines(10, 10, 8, 8, 8, 10, 10, 10),
+	})
+
+	report, err := Run(context.Background(), testConfig(), data, Options{Slippage: 0.001}, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Default", report.Strategy)
+	assert.Equal(t, 8, report.Ticks)
+	assert.Equal(t, int64(4), report.Trades, "BTC to ETH on the drop and back on the recovery, through the bridge")
+	assert.Equal(t, "100", report.InitialValue.String())
+	assert.Equal(t, "100", report.HODLValue.String())
+
+	// 1 BTC sold at the 99.9 bid buys 12.45 ETH at the 8.008 ask after fees,
+	// which are sold at 9.99 for 1.24 BTC at 100.1.
+	assert.InDelta(t, 124.0037, report.FinalValue.InexactFloat64(), 1e-4)
+	assert.InDelta(t, 0.24, report.Return(), 1e-4)
+	assert.Zero(t, report.HODLReturn())
+	// Slippage and fees cost 0.4% of the value on the first jump.
+	assert.InDelta(t, 0.004, report.MaxDrawdown, 1e-4)
+
+	var out bytes.Buffer
+	require.NoError(t, report.Print(&out))
+	assert.Contains(t, out.String(), "Trades         4")
+}
+
+func TestRun_RejectsAnEmptyPeriod(t *testing.T) {
+	data := NewDataset(map[string][]Kline{"BTCUSDT": minuteKlines(100, 100)})
+
+	_, err := Run(context.Background(), testConfig(), data, Options{Start: testStart.Add(time.Hour)}, zap.NewNop())
+	assert.ErrorContains(t, err, "nothing to backtest")
+}
@@ -0,0 +1,38 @@
+package backtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTime parses a date or an RFC 3339 timestamp given on the command line.
+// An empty value is the zero time.
+func ParseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// ParseBalances parses starting balances given on the command line, a list
+// like BTC=0.01,USDT=100.
+func ParseBalances(value string) (map[string]float64, error) {
+	balances := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		asset, qty, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected ASSET=QUANTITY, got %q", entry)
+		}
+		amount, err := strconv.ParseFloat(qty, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity of %s: %w", asset, err)
+		}
+		balances[strings.ToUpper(asset)] = amount
+	}
+	return balances, nil
+}
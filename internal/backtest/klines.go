@@ -0,0 +1,193 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// KlineInterval is the only kline interval the backtest replays.
+const KlineInterval = time.Minute
+
+// Kline is one candle of Binance's public kline data.
+type Kline struct {
+	OpenTime time.Time
+	Open     decimal.Decimal
+	High     decimal.Decimal
+	Low      decimal.Decimal
+	Close    decimal.Decimal
+	Volume   decimal.Decimal
+}
+
+// ParseKlines reads klines in the CSV format of Binance's public data dump
+// (data.binance.vision): open time, open, high, low, close, volume, close time,
+// quote volume, trade count, taker buy volume, taker buy quote volume, ignore.
+// A header row is skipped. Open times are in milliseconds, or in microseconds
+// as in the spot dumps since 2025.
+func ParseKlines(r io.Reader) ([]Kline, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var klines []Kline
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return klines, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if len(record) < 6 {
+			return nil, fmt.Errorf("line %d: expected at least 6 fields, got %d", line, len(record))
+		}
+
+		openTime, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return nil, fmt.Errorf("line %d: invalid open time %q", line, record[0])
+		}
+		// Millisecond timestamps have 13 digits, microsecond ones 16.
+		if openTime > 1e14 {
+			openTime /= 1000
+		}
+
+		k := Kline{OpenTime: time.UnixMilli(openTime).UTC()}
+		for i, field := range []*decimal.Decimal{&k.Open, &k.High, &k.Low, &k.Close, &k.Volume} {
+			if *field, err = decimal.NewFromString(record[i+1]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", line, record[i+1])
+			}
+		}
+		klines = append(klines, k)
+	}
+}
+
+// LoadKlines reads a kline CSV file, see ParseKlines.
+func LoadKlines(path string) ([]Kline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	klines, err := ParseKlines(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return klines, nil
+}
+
+// Dataset holds the 1m klines of a set of symbols, sorted by open time. It is
+// read-only once loaded, so concurrent backtests can share it.
+type Dataset struct {
+	klines map[string][]Kline
+}
+
+// NewDataset creates a dataset from klines by symbol. Klines are sorted and
+// duplicate open times, e.g. from overlapping files, are dropped.
+func NewDataset(klines map[string][]Kline) *Dataset {
+	d := &Dataset{klines: make(map[string][]Kline, len(klines))}
+	for symbol, ks := range klines {
+		sorted := append([]Kline(nil), ks...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].OpenTime.Before(sorted[j].OpenTime) })
+
+		unique := sorted[:0]
+		for _, k := range sorted {
+			if len(unique) > 0 && unique[len(unique)-1].OpenTime.Equal(k.OpenTime) {
+				continue
+			}
+			unique = append(unique, k)
+		}
+		if len(unique) > 0 {
+			d.klines[symbol] = unique
+		}
+	}
+	return d
+}
+
+// LoadDataset reads every kline CSV file in dir. Files must be named like the
+// ones in Binance's data dump, e.g. BTCUSDT-1m-2024-01.csv, as the symbol and
+// interval are taken from the name. Several files of a symbol are merged.
+func LoadDataset(dir string) (*Dataset, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no kline CSV files found in %s", dir)
+	}
+
+	klines := make(map[string][]Kline)
+	for _, path := range paths {
+		parts := strings.SplitN(filepath.Base(path), "-", 3)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("cannot tell the symbol and interval of %s, expected a name like BTCUSDT-1m-2024-01.csv", path)
+		}
+		symbol, interval := strings.ToUpper(parts[0]), parts[1]
+		if interval != "1m" {
+			return nil, fmt.Errorf("%s has %s klines, only 1m klines are supported", path, interval)
+		}
+
+		ks, err := LoadKlines(path)
+		if err != nil {
+			return nil, err
+		}
+		klines[symbol] = append(klines[symbol], ks...)
+	}
+	return NewDataset(klines), nil
+}
+
+// Symbols returns the symbols in the dataset, sorted.
+func (d *Dataset) Symbols() []string {
+	symbols := make([]string, 0, len(d.klines))
+	for symbol := range d.klines {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Start returns when the first kline of the dataset closes, the earliest time
+// a price is known.
+func (d *Dataset) Start() time.Time {
+	var start time.Time
+	for _, ks := range d.klines {
+		if t := ks[0].OpenTime.Add(KlineInterval); start.IsZero() || t.Before(start) {
+			start = t
+		}
+	}
+	return start
+}
+
+// End returns when the last kline of the dataset closes.
+func (d *Dataset) End() time.Time {
+	var end time.Time
+	for _, ks := range d.klines {
+		if t := ks[len(ks)-1].OpenTime.Add(KlineInterval); t.After(end) {
+			end = t
+		}
+	}
+	return end
+}
+
+// Close returns the close price of the last kline of symbol that closed at or
+// before at, so a backtest never sees a price from its future. It returns false
+// if no kline of symbol closed by then.
+func (d *Dataset) Close(symbol string, at time.Time) (decimal.Decimal, bool) {
+	ks := d.klines[symbol]
+	i := sort.Search(len(ks), func(i int) bool { return ks[i].OpenTime.Add(KlineInterval).After(at) })
+	if i == 0 {
+		return decimal.Zero, false
+	}
+	return ks[i-1].Close, true
+}
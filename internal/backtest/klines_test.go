@@ -0,0 +1,71 @@
+package backtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKlines(t *testing.T) {
+	csv := `open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore
+1704067200000,42283.58,42298.62,42261.02,42298.61,35.92724,1704067259999,1519286.10,1327,17.82306,753740.97,0
+1704067260000000,42298.62,42320.00,42298.61,42320.00,21.07582,1704067319999999,891693.42,984,14.63529,619191.43,0
+`
+	klines, err := ParseKlines(strings.NewReader(csv))
+	require.NoError(t, err)
+	require.Len(t, klines, 2)
+
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), klines[0].OpenTime)
+	assert.Equal(t, "42298.61", klines[0].Close.String())
+	// Microsecond timestamps are read as milliseconds.
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 1, 0, 0, time.UTC), klines[1].OpenTime)
+	assert.Equal(t, "21.07582", klines[1].Volume.String())
+
+	_, err = ParseKlines(strings.NewReader("1704067200000,42283.58,x,42261.02,42298.61,35.9\n"))
+	assert.ErrorContains(t, err, `line 1: invalid number "x"`)
+}
+
+func TestDataset_Close(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := NewDataset(map[string][]Kline{"BTCUSDT": {
+		{OpenTime: t0.Add(time.Minute), Close: decimal.NewFromInt(101)},
+		{OpenTime: t0, Close: decimal.NewFromInt(100)},
+		{OpenTime: t0, Close: decimal.NewFromInt(100)},
+	}})
+
+	assert.Equal(t, t0.Add(time.Minute), data.Start())
+	assert.Equal(t, t0.Add(2*time.Minute), data.End())
+
+	_, ok := data.Close("BTCUSDT", t0.Add(59*time.Second))
+	assert.False(t, ok, "the first kline has not closed yet")
+	price, ok := data.Close("BTCUSDT", t0.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, "100", price.String())
+	price, _ = data.Close("BTCUSDT", t0.Add(time.Hour))
+	assert.Equal(t, "101", price.String())
+}
+
+func TestLoadDataset(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	write("BTCUSDT-1m-2024-01.csv", "1704067200000,1,1,1,1,1,1704067259999,0,0,0,0,0\n")
+	write("BTCUSDT-1m-2024-02.csv", "1706745600000,2,2,2,2,1,1706745659999,0,0,0,0,0\n")
+	write("ETHUSDT-1m-2024-01.csv", "1704067200000,3,3,3,3,1,1704067259999,0,0,0,0,0\n")
+
+	data, err := LoadDataset(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTCUSDT", "ETHUSDT"}, data.Symbols())
+	assert.Len(t, data.klines["BTCUSDT"], 2)
+
+	write("BTCUSDT-1h-2024-01.csv", "")
+	_, err = LoadDataset(dir)
+	assert.ErrorContains(t, err, "only 1m klines are supported")
+}
@@ -0,0 +1,142 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/clock"
+	"github.com/shopspring/decimal"
+)
+
+// errMarketDataOnly is returned by the account and order endpoints of Market.
+// Orders are filled by a binance.PaperClient on top of it.
+var errMarketDataOnly = errors.New("the backtest market only serves market data")
+
+// Market serves a Dataset as the market data endpoints of Binance at the time
+// of a clock, usually a clock.Manual that the backtest moves forward. The price of a symbol is the
+// close of its last finished kline; the book is synthesised around it with the
+// slippage as the distance to the best bid and ask, so market orders filled by
+// a PaperClient lose the slippage on every order.
+type Market struct {
+	data     *Dataset
+	clock    clock.Clock
+	symbols  []binance.SymbolInfo
+	slippage decimal.Decimal
+}
+
+// ensure Market implements the interface
+var _ binance.RestClientInterface = (*Market)(nil)
+
+// NewMarket creates a market over data. Exchange info lists the symbols of
+// data made of two of assets, e.g. BTCUSDT for BTC and USDT, as trading with a
+// step size of 1e-8. The slippage is a fraction of the price, e.g. 0.0005.
+func NewMarket(data *Dataset, clk clock.Clock, assets []string, slippage float64) *Market {
+	m := &Market{data: data, clock: clk, slippage: decimal.NewFromFloat(slippage)}
+	for _, symbol := range data.Symbols() {
+		base, quote, ok := splitSymbol(symbol, assets)
+		if !ok {
+			continue
+		}
+		m.symbols = append(m.symbols, binance.SymbolInfo{
+			Symbol:     symbol,
+			Status:     binance.SymbolStatusTrading,
+			BaseAsset:  base,
+			QuoteAsset: quote,
+			Filters: []binance.Filter{{
+				FilterType: binance.FilterTypeLotSize,
+				MinQty:     "0.00000001",
+				MaxQty:     "9000000000",
+				StepSize:   "0.00000001",
+			}},
+		})
+	}
+	return m
+}
+
+// splitSymbol finds the base and quote asset of symbol among assets.
+func splitSymbol(symbol string, assets []string) (base, quote string, ok bool) {
+	known := make(map[string]bool, len(assets))
+	for _, a := range assets {
+		known[strings.ToUpper(a)] = true
+	}
+	// Try the longest quote first, e.g. USDT before a coin named T.
+	quotes := append([]string(nil), assets...)
+	sort.Slice(quotes, func(i, j int) bool { return len(quotes[i]) > len(quotes[j]) })
+	for _, q := range quotes {
+		q = strings.ToUpper(q)
+		if b := strings.TrimSuffix(symbol, q); b != symbol && known[b] {
+			return b, q, true
+		}
+	}
+	return "", "", false
+}
+
+// Price returns the price of symbol at the clock's time.
+func (m *Market) Price(symbol string) (decimal.Decimal, bool) {
+	return m.data.Close(symbol, m.clock.Now())
+}
+
+// GetServerTime returns the clock's time.
+func (m *Market) GetServerTime(ctx context.Context) (int64, error) {
+	return m.clock.Now().UnixMilli(), nil
+}
+
+// GetAllTickerPrices returns the price of every symbol that has one yet.
+func (m *Market) GetAllTickerPrices(ctx context.Context) (map[string]string, error) {
+	now := m.clock.Now()
+	prices := make(map[string]string)
+	for _, symbol := range m.data.Symbols() {
+		if price, ok := m.data.Close(symbol, now); ok {
+			prices[symbol] = price.String()
+		}
+	}
+	return prices, nil
+}
+
+// GetAllBookTickers returns a book of every symbol with the slippage on both
+// sides of its price.
+func (m *Market) GetAllBookTickers(ctx context.Context) (map[string]binance.BookTicker, error) {
+	now := m.clock.Now()
+	one := decimal.NewFromInt(1)
+	tickers := make(map[string]binance.BookTicker)
+	for _, symbol := range m.data.Symbols() {
+		price, ok := m.data.Close(symbol, now)
+		if !ok {
+			continue
+		}
+		tickers[symbol] = binance.BookTicker{
+			Symbol:   symbol,
+			BidPrice: price.Mul(one.Sub(m.slippage)).String(),
+			AskPrice: price.Mul(one.Add(m.slippage)).String(),
+		}
+	}
+	return tickers, nil
+}
+
+// GetExchangeInfo returns the symbols synthesised by NewMarket.
+func (m *Market) GetExchangeInfo(ctx context.Context) (*binance.ExchangeInfoResponse, error) {
+	return &binance.ExchangeInfoResponse{Symbols: append([]binance.SymbolInfo(nil), m.symbols...)}, nil
+}
+
+// CreateOrder always fails, see errMarketDataOnly.
+func (m *Market) CreateOrder(ctx context.Context, symbol, side string, quantity decimal.Decimal, clientOrderID string) (*binance.CreateOrderResponse, error) {
+	return nil, errMarketDataOnly
+}
+
+// GetOrder always fails, see errMarketDataOnly.
+func (m *Market) GetOrder(ctx context.Context, symbol string, orderID int64) (*binance.OrderResponse, error) {
+	return nil, errMarketDataOnly
+}
+
+// GetOrderByClientID always fails, see errMarketDataOnly.
+func (m *Market) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*binance.OrderResponse, error) {
+	return nil, errMarketDataOnly
+}
+
+// GetAccount always fails, see errMarketDataOnly.
+func (m *Market) GetAccount(ctx context.Context) (*binance.AccountResponse, error) {
+	return nil, errMarketDataOnly
+}
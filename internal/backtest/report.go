@@ -0,0 +1,54 @@
+package backtest
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Report summarises a backtest run. Values are in the bridge.
+type Report struct {
+	Strategy     string
+	Start        time.Time
+	End          time.Time
+	Ticks        int
+	Trades       int64
+	InitialValue decimal.Decimal
+	FinalValue   decimal.Decimal
+	HODLValue    decimal.Decimal // The starting balances, held untouched until the end
+	MaxDrawdown  float64         // Largest drop from a peak in value, as a fraction of the peak
+}
+
+// Return is the change in value over the run, as a fraction of the initial value.
+func (r *Report) Return() float64 {
+	return change(r.InitialValue, r.FinalValue)
+}
+
+// HODLReturn is the return of holding the starting balances instead.
+func (r *Report) HODLReturn() float64 {
+	return change(r.InitialValue, r.HODLValue)
+}
+
+func change(from, to decimal.Decimal) float64 {
+	if !from.IsPositive() {
+		return 0
+	}
+	return to.Sub(from).Div(from).InexactFloat64()
+}
+
+// Print writes the report as a table.
+func (r *Report) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Strategy\t%s\n", r.Strategy)
+	fmt.Fprintf(tw, "Period\t%s - %s\n", r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339))
+	fmt.Fprintf(tw, "Ticks\t%d\n", r.Ticks)
+	fmt.Fprintf(tw, "Trades\t%d\n", r.Trades)
+	fmt.Fprintf(tw, "Initial value\t%s\n", r.InitialValue.StringFixed(2))
+	fmt.Fprintf(tw, "Final value\t%s\t%+.2f%%\n", r.FinalValue.StringFixed(2), r.Return()*100)
+	fmt.Fprintf(tw, "HODL value\t%s\t%+.2f%%\n", r.HODLValue.StringFixed(2), r.HODLReturn()*100)
+	fmt.Fprintf(tw, "Max drawdown\t%.2f%%\n", r.MaxDrawdown*100)
+	return tw.Flush()
+}
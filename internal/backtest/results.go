@@ -0,0 +1,106 @@
+package backtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// resultRow is a Result as a row of the results table.
+type resultRow struct {
+	Rank         int      `json:"rank"`
+	ScoutMargin  float64  `json:"scout_margin"`
+	TickInterval int      `json:"tick_interval"`
+	FeeRate      float64  `json:"fee_rate"`
+	Coins        []string `json:"coins"`
+	Return       float64  `json:"return"`
+	HODLReturn   float64  `json:"hodl_return"`
+	MaxDrawdown  float64  `json:"max_drawdown"`
+	Trades       int64    `json:"trades"`
+	FinalValue   string   `json:"final_value"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func resultRows(results []Result) []resultRow {
+	rows := make([]resultRow, len(results))
+	for i, r := range results {
+		row := resultRow{
+			Rank:         i + 1,
+			ScoutMargin:  r.ScoutMargin,
+			TickInterval: r.TickInterval,
+			FeeRate:      r.FeeRate,
+			Coins:        r.Coins,
+		}
+		if r.Report != nil {
+			row.Return = r.Report.Return()
+			row.HODLReturn = r.Report.HODLReturn()
+			row.MaxDrawdown = r.Report.MaxDrawdown
+			row.Trades = r.Report.Trades
+			row.FinalValue = r.Report.FinalValue.StringFixed(8)
+		}
+		if r.Err != nil {
+			row.Error = r.Err.Error()
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// WriteJSON writes the results as a JSON array, ranked in the order given.
+func WriteJSON(w io.Writer, results []Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(resultRows(results))
+}
+
+// WriteCSV writes the results as CSV with a header row, ranked in the order
+// given. The coins of a trial are separated by spaces.
+func WriteCSV(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"rank", "scout_margin", "tick_interval", "fee_rate", "coins", "return", "hodl_return", "max_drawdown", "trades", "final_value", "error"})
+	for _, row := range resultRows(results) {
+		writer.Write([]string{
+			strconv.Itoa(row.Rank),
+			formatFloat(row.ScoutMargin),
+			strconv.Itoa(row.TickInterval),
+			formatFloat(row.FeeRate),
+			strings.Join(row.Coins, " "),
+			formatFloat(row.Return),
+			formatFloat(row.HODLReturn),
+			formatFloat(row.MaxDrawdown),
+			strconv.FormatInt(row.Trades, 10),
+			row.FinalValue,
+			row.Error,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// PrintResults writes the first limit results as a table, all of them if
+// limit is not positive.
+func PrintResults(w io.Writer, results []Result, limit int) error {
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Rank\tScout margin\tTick\tFee rate\tCoins\tReturn\tHODL\tMax drawdown\tTrades")
+	for _, row := range resultRows(results) {
+		if row.Error != "" {
+			fmt.Fprintf(tw, "%d\t%g\t%ds\t%g\t%s\terror: %s\n", row.Rank, row.ScoutMargin, row.TickInterval, row.FeeRate, strings.Join(row.Coins, ","), row.Error)
+			continue
+		}
+		fmt.Fprintf(tw, "%d\t%g\t%ds\t%g\t%s\t%+.2f%%\t%+.2f%%\t%.2f%%\t%d\n",
+			row.Rank, row.ScoutMargin, row.TickInterval, row.FeeRate, strings.Join(row.Coins, ","),
+			row.Return*100, row.HODLReturn*100, row.MaxDrawdown*100, row.Trades)
+	}
+	return tw.Flush()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,196 @@
+package backtest
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+
+	"binance-trade-bot-go/internal/config"
+	"go.uber.org/zap"
+)
+
+// Grid is the parameter space of a sweep. A dimension without values keeps
+// the value of the base config.
+type Grid struct {
+	ScoutMargins  []float64
+	TickIntervals []int // Seconds
+	FeeRates      []float64
+	CoinSets      [][]string
+}
+
+// Trial is one combination of the parameters of a Grid.
+type Trial struct {
+	ScoutMargin  float64  `json:"scout_margin"`
+	TickInterval int      `json:"tick_interval"`
+	FeeRate      float64  `json:"fee_rate"`
+	Coins        []string `json:"coins"`
+}
+
+// apply sets the parameters of the trial on cfg.
+func (t Trial) apply(cfg *config.Config) {
+	cfg.Trading.ScoutMargin = t.ScoutMargin
+	cfg.Trading.TickInterval = t.TickInterval
+	cfg.Trading.FeeRate = t.FeeRate
+	cfg.Trading.TradePairs = append([]string(nil), t.Coins...)
+	// A starting coin outside of the subset cannot be started from, fall back
+	// to the coin with the largest balance.
+	if !containsFold(t.Coins, cfg.Trading.StartingCoin) {
+		cfg.Trading.StartingCoin = ""
+	}
+}
+
+// Trials returns every combination of the grid, the full grid search.
+func (g Grid) Trials(base config.Trading) []Trial {
+	margins := g.ScoutMargins
+	if len(margins) == 0 {
+		margins = []float64{base.ScoutMargin}
+	}
+	ticks := g.TickIntervals
+	if len(ticks) == 0 {
+		ticks = []int{base.TickInterval}
+	}
+	fees := g.FeeRates
+	if len(fees) == 0 {
+		fees = []float64{base.FeeRate}
+	}
+	coinSets := g.CoinSets
+	if len(coinSets) == 0 {
+		coinSets = [][]string{base.TradePairs}
+	}
+
+	var trials []Trial
+	for _, coins := range coinSets {
+		for _, fee := range fees {
+			for _, tick := range ticks {
+				for _, margin := range margins {
+					trials = append(trials, Trial{ScoutMargin: margin, TickInterval: tick, FeeRate: fee, Coins: coins})
+				}
+			}
+		}
+	}
+	return trials
+}
+
+// Sample returns n combinations of the grid picked at random without
+// repetition, a random search. It returns the whole grid if it has no more
+// than n combinations.
+func (g Grid) Sample(base config.Trading, n int, rng *rand.Rand) []Trial {
+	trials := g.Trials(base)
+	if n >= len(trials) {
+		return trials
+	}
+	sample := make([]Trial, n)
+	for i, j := range rng.Perm(len(trials))[:n] {
+		sample[i] = trials[j]
+	}
+	return sample
+}
+
+// CoinSubsets returns every subset of size coins of coins, in order.
+func CoinSubsets(coins []string, size int) [][]string {
+	var subsets [][]string
+	var pick func(start int, subset []string)
+	pick = func(start int, subset []string) {
+		if len(subset) == size {
+			subsets = append(subsets, append([]string(nil), subset...))
+			return
+		}
+		for i := start; i <= len(coins)-(size-len(subset)); i++ {
+			pick(i+1, append(subset, coins[i]))
+		}
+	}
+	if size > 0 && size <= len(coins) {
+		pick(0, nil)
+	}
+	return subsets
+}
+
+// Result is the outcome of the backtest of a trial. Report is nil if the
+// backtest failed with Err.
+type Result struct {
+	Trial
+	Report *Report
+	Err    error
+}
+
+// Sweep backtests every trial over data with the strategy and settings of cfg,
+// running up to workers backtests in parallel. Results are returned in the
+// order of trials; a failed backtest is reported in its result and does not
+// stop the others.
+func Sweep(ctx context.Context, cfg config.Config, data *Dataset, opts Options, trials []Trial, workers int, log *zap.Logger) []Result {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]Result, len(trials))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				trialCfg := cfg
+				trials[i].apply(&trialCfg)
+				report, err := Run(ctx, trialCfg, data, opts, log)
+				results[i] = Result{Trial: trials[i], Report: report, Err: err}
+				log.Info("Backtest finished",
+					zap.Int("trial", i+1),
+					zap.Int("trials", len(trials)),
+					zap.Float64("scout_margin", trials[i].ScoutMargin),
+					zap.Int("tick_interval", trials[i].TickInterval),
+					zap.Float64("fee_rate", trials[i].FeeRate),
+					zap.Strings("coins", trials[i].Coins),
+					zap.Error(err))
+			}
+		}()
+	}
+	for i := range trials {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// Rankings results can be sorted by.
+const (
+	// RankByReturn puts the highest return first, the smaller drawdown first
+	// among equal returns.
+	RankByReturn = "return"
+	// RankByDrawdown puts the smallest drawdown first, the higher return first
+	// among equal drawdowns.
+	RankByDrawdown = "drawdown"
+)
+
+// Rank sorts results best first by the ranking, RankByReturn or
+// RankByDrawdown. Failed backtests go last.
+func Rank(results []Result, by string) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Report, results[j].Report
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if by == RankByDrawdown {
+			if a.MaxDrawdown != b.MaxDrawdown {
+				return a.MaxDrawdown < b.MaxDrawdown
+			}
+			return a.Return() > b.Return()
+		}
+		if a.Return() != b.Return() {
+			return a.Return() > b.Return()
+		}
+		return a.MaxDrawdown < b.MaxDrawdown
+	})
+}
+
+func containsFold(values []string, v string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package backtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGrid_Trials(t *testing.T) {
+	base := testConfig().Trading
+	grid := Grid{ScoutMargins: []float64{0.5, 1}, TickIntervals: []int{60, 300}}
+
+	trials := grid.Trials(base)
+	require.Len(t, trials, 4)
+	assert.Equal(t, Trial{ScoutMargin: 0.5, TickInterval: 60, FeeRate: 0.001, Coins: []string{"BTC", "ETH"}}, trials[0])
+	assert.Equal(t, Trial{ScoutMargin: 1, TickInterval: 300, FeeRate: 0.001, Coins: []string{"BTC", "ETH"}}, trials[3])
+
+	sample := grid.Sample(base, 2, rand.New(rand.NewSource(1)))
+	require.Len(t, sample, 2)
+	assert.NotEqual(t, sample[0], sample[1])
+	assert.Len(t, grid.Sample(base, 10, rand.New(rand.NewSource(1))), 4)
+}
+
+func TestCoinSubsets(t *testing.T) {
+	assert.Equal(t, [][]string{{"BTC", "ETH"}, {"BTC", "BNB"}, {"ETH", "BNB"}}, CoinSubsets([]string{"BTC", "ETH", "BNB"}, 2))
+	assert.Empty(t, CoinSubsets([]string{"BTC"}, 2))
+}
+
+func TestSweep(t *testing.T) {
+	data := NewDataset(map[string][]Kline{
+		"BTCUSDT": minuteKlines(100, 100, 100, 100, 100, 100, 100, 100),
+		"ETHUSDT": minuteKlines(10, 10, 8, 8, 8, 10, 10, 10),
+	})
+	trials := []Trial{
+		{ScoutMargin: 30, TickInterval: 60, FeeRate: 0.001, Coins: []string{"BTC", "ETH"}},
+		{ScoutMargin: 0, TickInterval: 60, FeeRate: 0.001, Coins: []string{"BTC", "ETH"}},
+		{ScoutMargin: 0, TickInterval: 60, FeeRate: 0.001, Coins: []string{"ETH"}},
+	}
+
+	results := Sweep(context.Background(), testConfig(), data, Options{Slippage: 0.001}, trials, 2, zap.NewNop())
+
+	require.Len(t, results, 3)
+	for i, r := range results {
+		assert.Equal(t, trials[i], r.Trial)
+	}
+	// A 30% margin is never met, so the first trial holds BTC throughout.
+	require.NoError(t, results[0].Err)
+	assert.Zero(t, results[0].Report.Trades)
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, int64(4), results[1].Report.Trades)
+	// Without BTC among the coins there is no balance to start from.
+	assert.ErrorContains(t, results[2].Err, "no balance held in any enabled coin")
+
+	Rank(results, RankByReturn)
+	assert.Equal(t, 0.0, results[0].ScoutMargin)
+	assert.Equal(t, 30.0, results[1].ScoutMargin)
+	assert.Nil(t, results[2].Report)
+
+	Rank(results, RankByDrawdown)
+	assert.Equal(t, 30.0, results[0].ScoutMargin)
+}
+
+func TestWriteResults(t *testing.T) {
+	results := []Result{
+		{
+			Trial:  Trial{ScoutMargin: 0.5, TickInterval: 60, FeeRate: 0.001, Coins: []string{"BTC", "ETH"}},
+			Report: &Report{InitialValue: decimal.NewFromInt(100), FinalValue: decimal.NewFromInt(110), HODLValue: decimal.NewFromInt(105), MaxDrawdown: 0.02, Trades: 6},
+		},
+		{Trial: Trial{ScoutMargin: 1, TickInterval: 60, FeeRate: 0.001, Coins: []string{"ETH"}}, Err: errors.New("no balance")},
+	}
+
+	var csv bytes.Buffer
+	require.NoError(t, WriteCSV(&csv, results))
+	assert.Equal(t, strings.Join([]string{
+		"rank,scout_margin,tick_interval,fee_rate,coins,return,hodl_return,max_drawdown,trades,final_value,error",
+		"1,0.5,60,0.001,BTC ETH,0.1,0.05,0.02,6,110.00000000,",
+		"2,1,60,0.001,ETH,0,0,0,0,,no balance",
+	}, "\n")+"\n", csv.String())
+
+	var out bytes.Buffer
+	require.NoError(t, WriteJSON(&out, results))
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &rows))
+	require.Len(t, rows, 2)
+	assert.Equal(t, 0.1, rows[0]["return"])
+	assert.Equal(t, []interface{}{"BTC", "ETH"}, rows[0]["coins"])
+	assert.NotContains(t, rows[0], "error")
+	assert.Equal(t, "no balance", rows[1]["error"])
+}
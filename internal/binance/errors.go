@@ -0,0 +1,64 @@
+package binance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Binance error codes the client treats specially.
+// See https://developers.binance.com/docs/binance-spot-api-docs/errors
+const (
+	ErrCodeTooManyRequests     = -1003
+	ErrCodeFilterFailure       = -1013
+	ErrCodeTimestampOutOfSync  = -1021
+	ErrCodeInsufficientBalance = -2010
+	ErrCodeNoSuchOrder         = -2013
+)
+
+// Sentinels for the common error codes, to be used with errors.Is.
+// They match any APIError with the same code, whatever its message.
+var (
+	ErrTooManyRequests     = &APIError{Code: ErrCodeTooManyRequests, Message: "too many requests"}
+	ErrFilterFailure       = &APIError{Code: ErrCodeFilterFailure, Message: "filter failure"}
+	ErrTimestampOutOfSync  = &APIError{Code: ErrCodeTimestampOutOfSync, Message: "timestamp outside of recvWindow"}
+	ErrInsufficientBalance = &APIError{Code: ErrCodeInsufficientBalance, Message: "account has insufficient balance for requested action"}
+	ErrOrderNotFound       = &APIError{Code: ErrCodeNoSuchOrder, Message: "order does not exist"}
+)
+
+// ErrOrderStatusUnknown is returned when an order request failed in a way that
+// leaves open whether Binance placed the order, e.g. a timeout or a 5xx
+// response. The order must be looked up before it is sent again.
+var ErrOrderStatusUnknown = errors.New("order status unknown")
+
+// APIError is an error response returned by the Binance API.
+type APIError struct {
+	HTTPStatus int    `json:"-"`
+	Code       int    `json:"code"`
+	Message    string `json:"msg"`
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.HTTPStatus == 0 {
+		return fmt.Sprintf("binance API error %d: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("binance API error %d: %s (HTTP %d)", e.Code, e.Message, e.HTTPStatus)
+}
+
+// Is reports whether target is an APIError with the same code.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code != 0 && t.Code == e.Code
+}
+
+// parseAPIError builds an APIError from an error response body. Bodies that
+// are not Binance error payloads, e.g. from a proxy, are kept as the message.
+func parseAPIError(status int, body []byte) *APIError {
+	apiErr := &APIError{HTTPStatus: status}
+	if err := json.Unmarshal(body, apiErr); err != nil || apiErr.Code == 0 {
+		apiErr.Code = 0
+		apiErr.Message = string(body)
+	}
+	return apiErr
+}
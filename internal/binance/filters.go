@@ -0,0 +1,167 @@
+package binance
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Symbol filter types.
+// See https://developers.binance.com/docs/binance-spot-api-docs/filters
+const (
+	FilterTypePrice         = "PRICE_FILTER"
+	FilterTypeLotSize       = "LOT_SIZE"
+	FilterTypeMarketLotSize = "MARKET_LOT_SIZE"
+	FilterTypeMinNotional   = "MIN_NOTIONAL"
+	FilterTypeNotional      = "NOTIONAL"
+)
+
+// QuantityFilter is a LOT_SIZE or MARKET_LOT_SIZE filter. A zero field is not enforced.
+type QuantityFilter struct {
+	MinQty   decimal.Decimal
+	MaxQty   decimal.Decimal
+	StepSize decimal.Decimal
+}
+
+// RoundDown floors quantity to a multiple of the step size. Without a step
+// size the quantity is returned unchanged.
+func (q QuantityFilter) RoundDown(quantity decimal.Decimal) decimal.Decimal {
+	if !q.StepSize.IsPositive() {
+		return quantity
+	}
+	return quantity.Div(q.StepSize).Floor().Mul(q.StepSize)
+}
+
+// NotionalFilter limits the value (price * quantity) of an order. A zero
+// limit, or one that does not apply to market orders, is not enforced.
+type NotionalFilter struct {
+	Type             string // NOTIONAL or MIN_NOTIONAL
+	MinNotional      decimal.Decimal
+	MaxNotional      decimal.Decimal
+	ApplyMinToMarket bool
+	ApplyMaxToMarket bool
+}
+
+// SymbolFilters are the parsed filters of a symbol.
+type SymbolFilters struct {
+	MinPrice      decimal.Decimal
+	MaxPrice      decimal.Decimal
+	TickSize      decimal.Decimal
+	LotSize       QuantityFilter
+	MarketLotSize QuantityFilter
+	// Notional holds the NOTIONAL filter, or the legacy MIN_NOTIONAL filter on
+	// symbols that still use it.
+	Notional NotionalFilter
+}
+
+// FilterError describes an order that violates one of the symbol's filters.
+// It matches ErrFilterFailure with errors.Is, like the exchange's own -1013.
+type FilterError struct {
+	Symbol string
+	Filter string
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s filter failure for %s: %s", e.Filter, e.Symbol, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrFilterFailure) hold for local filter checks.
+func (e *FilterError) Is(target error) bool {
+	return target == ErrFilterFailure
+}
+
+// ParseFilters parses the filters of the symbol into decimals. Filter types
+// the bot does not use are ignored.
+func (s SymbolInfo) ParseFilters() (SymbolFilters, error) {
+	var filters SymbolFilters
+	for _, f := range s.Filters {
+		var err error
+		switch f.FilterType {
+		case FilterTypePrice:
+			if filters.MinPrice, err = parseDecimal(f.MinPrice); err != nil {
+				break
+			}
+			if filters.MaxPrice, err = parseDecimal(f.MaxPrice); err != nil {
+				break
+			}
+			filters.TickSize, err = parseDecimal(f.TickSize)
+		case FilterTypeLotSize:
+			filters.LotSize, err = parseQuantityFilter(f)
+		case FilterTypeMarketLotSize:
+			filters.MarketLotSize, err = parseQuantityFilter(f)
+		case FilterTypeMinNotional:
+			filters.Notional.Type = f.FilterType
+			filters.Notional.MinNotional, err = parseDecimal(f.MinNotional)
+			filters.Notional.ApplyMinToMarket = f.ApplyToMarket
+		case FilterTypeNotional:
+			filters.Notional.Type = f.FilterType
+			filters.Notional.ApplyMinToMarket = f.ApplyMinToMarket
+			filters.Notional.ApplyMaxToMarket = f.ApplyMaxToMarket
+			if filters.Notional.MinNotional, err = parseDecimal(f.MinNotional); err == nil {
+				filters.Notional.MaxNotional, err = parseDecimal(f.MaxNotional)
+			}
+		}
+		if err != nil {
+			return SymbolFilters{}, fmt.Errorf("invalid %s filter for %s: %w", f.FilterType, s.Symbol, err)
+		}
+	}
+	return filters, nil
+}
+
+// ValidateMarketOrder checks a MARKET order of quantity at the expected price
+// against the filters. Binance values market orders at the average price of
+// the last avgPriceMins minutes for the notional filters, so the check is an
+// estimate close to the limits. PRICE_FILTER does not apply to market orders.
+func (f SymbolFilters) ValidateMarketOrder(symbol string, quantity, price decimal.Decimal) error {
+	fail := func(filter, format string, args ...interface{}) error {
+		return &FilterError{Symbol: symbol, Filter: filter, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	for _, q := range []struct {
+		name   string
+		filter QuantityFilter
+	}{{FilterTypeLotSize, f.LotSize}, {FilterTypeMarketLotSize, f.MarketLotSize}} {
+		if q.filter.MinQty.IsPositive() && quantity.LessThan(q.filter.MinQty) {
+			return fail(q.name, "quantity %s is below the minimum of %s", quantity, q.filter.MinQty)
+		}
+		if q.filter.MaxQty.IsPositive() && quantity.GreaterThan(q.filter.MaxQty) {
+			return fail(q.name, "quantity %s is above the maximum of %s", quantity, q.filter.MaxQty)
+		}
+		// Binance measures the step from minQty, which is itself a multiple of the step.
+		if q.filter.StepSize.IsPositive() && !quantity.Mod(q.filter.StepSize).IsZero() {
+			return fail(q.name, "quantity %s is not a multiple of the step size %s", quantity, q.filter.StepSize)
+		}
+	}
+
+	notional := quantity.Mul(price)
+	if f.Notional.ApplyMinToMarket && f.Notional.MinNotional.IsPositive() && notional.LessThan(f.Notional.MinNotional) {
+		return fail(f.Notional.Type, "order value %s is below the minimum of %s", notional, f.Notional.MinNotional)
+	}
+	if f.Notional.ApplyMaxToMarket && f.Notional.MaxNotional.IsPositive() && notional.GreaterThan(f.Notional.MaxNotional) {
+		return fail(f.Notional.Type, "order value %s is above the maximum of %s", notional, f.Notional.MaxNotional)
+	}
+	return nil
+}
+
+func parseQuantityFilter(f Filter) (QuantityFilter, error) {
+	var q QuantityFilter
+	var err error
+	if q.MinQty, err = parseDecimal(f.MinQty); err != nil {
+		return q, err
+	}
+	if q.MaxQty, err = parseDecimal(f.MaxQty); err != nil {
+		return q, err
+	}
+	q.StepSize, err = parseDecimal(f.StepSize)
+	return q, err
+}
+
+// parseDecimal parses a decimal string of a filter. An empty string is zero.
+func parseDecimal(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(value)
+}
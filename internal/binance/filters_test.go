@@ -0,0 +1,133 @@
+package binance
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/quick"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSymbolInfo = `{
+	"symbol": "BTCUSDT",
+	"status": "TRADING",
+	"baseAsset": "BTC",
+	"quoteAsset": "USDT",
+	"filters": [
+		{"filterType": "PRICE_FILTER", "minPrice": "0.01000000", "maxPrice": "1000000.00000000", "tickSize": "0.01000000"},
+		{"filterType": "LOT_SIZE", "minQty": "0.00001000", "maxQty": "9000.00000000", "stepSize": "0.00001000"},
+		{"filterType": "ICEBERG_PARTS", "limit": 10},
+		{"filterType": "MARKET_LOT_SIZE", "minQty": "0.00000000", "maxQty": "85.50000000", "stepSize": "0.00000000"},
+		{"filterType": "NOTIONAL", "minNotional": "5.00000000", "applyMinToMarket": true, "maxNotional": "9000000.00000000", "applyMaxToMarket": false, "avgPriceMins": 5}
+	]
+}`
+
+func TestSymbolInfo_ParseFilters(t *testing.T) {
+	var info SymbolInfo
+	require.NoError(t, json.Unmarshal([]byte(testSymbolInfo), &info))
+
+	filters, err := info.ParseFilters()
+
+	require.NoError(t, err)
+	for name, tc := range map[string]struct {
+		value    decimal.Decimal
+		expected string
+	}{
+		"minPrice":                 {filters.MinPrice, "0.01"},
+		"maxPrice":                 {filters.MaxPrice, "1000000"},
+		"tickSize":                 {filters.TickSize, "0.01"},
+		"LOT_SIZE minQty":          {filters.LotSize.MinQty, "0.00001"},
+		"LOT_SIZE maxQty":          {filters.LotSize.MaxQty, "9000"},
+		"LOT_SIZE stepSize":        {filters.LotSize.StepSize, "0.00001"},
+		"MARKET_LOT_SIZE minQty":   {filters.MarketLotSize.MinQty, "0"},
+		"MARKET_LOT_SIZE maxQty":   {filters.MarketLotSize.MaxQty, "85.5"},
+		"MARKET_LOT_SIZE stepSize": {filters.MarketLotSize.StepSize, "0"},
+		"minNotional":              {filters.Notional.MinNotional, "5"},
+		"maxNotional":              {filters.Notional.MaxNotional, "9000000"},
+	} {
+		assert.Equal(t, tc.expected, tc.value.String(), name)
+	}
+	assert.Equal(t, FilterTypeNotional, filters.Notional.Type)
+	assert.True(t, filters.Notional.ApplyMinToMarket)
+	assert.False(t, filters.Notional.ApplyMaxToMarket)
+
+	t.Run("Legacy MIN_NOTIONAL", func(t *testing.T) {
+		info := SymbolInfo{Symbol: "ETHBTC", Filters: []Filter{{FilterType: FilterTypeMinNotional, MinNotional: "0.0001", ApplyToMarket: true}}}
+		filters, err := info.ParseFilters()
+		require.NoError(t, err)
+		assert.Equal(t, FilterTypeMinNotional, filters.Notional.Type)
+		assert.Equal(t, "0.0001", filters.Notional.MinNotional.String())
+		assert.True(t, filters.Notional.ApplyMinToMarket)
+	})
+
+	t.Run("Malformed number", func(t *testing.T) {
+		info := SymbolInfo{Symbol: "ETHBTC", Filters: []Filter{{FilterType: FilterTypeLotSize, MinQty: "lots"}}}
+		_, err := info.ParseFilters()
+		assert.ErrorContains(t, err, "invalid LOT_SIZE filter for ETHBTC")
+	})
+}
+
+func TestSymbolFilters_ValidateMarketOrder(t *testing.T) {
+	var info SymbolInfo
+	require.NoError(t, json.Unmarshal([]byte(testSymbolInfo), &info))
+	filters, err := info.ParseFilters()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		quantity string
+		price    string
+		filter   string // violated filter, empty if the order is valid
+	}{
+		{name: "Valid order", quantity: "0.01234", price: "60000"},
+		{name: "Below LOT_SIZE minQty", quantity: "0.000001", price: "60000", filter: FilterTypeLotSize},
+		{name: "Off the LOT_SIZE step", quantity: "0.012345", price: "60000", filter: FilterTypeLotSize},
+		{name: "Above MARKET_LOT_SIZE maxQty", quantity: "90", price: "60000", filter: FilterTypeMarketLotSize},
+		{name: "Below NOTIONAL minNotional", quantity: "0.00005", price: "60000", filter: FilterTypeNotional},
+		// maxNotional does not apply to market orders on this symbol
+		{name: "Above unenforced maxNotional", quantity: "85", price: "200000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := filters.ValidateMarketOrder("BTCUSDT", decimal.RequireFromString(tc.quantity), decimal.RequireFromString(tc.price))
+			if tc.filter == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var filterErr *FilterError
+			require.ErrorAs(t, err, &filterErr)
+			assert.Equal(t, tc.filter, filterErr.Filter)
+			assert.ErrorIs(t, err, ErrFilterFailure)
+			assert.Contains(t, err.Error(), tc.filter+" filter failure for BTCUSDT")
+		})
+	}
+}
+
+func TestQuantityFilter_RoundDown(t *testing.T) {
+	filter := QuantityFilter{StepSize: decimal.RequireFromString("0.001")}
+
+	assert.Equal(t, "0.3", filter.RoundDown(decimal.RequireFromString("0.3")).String())
+	assert.Equal(t, "0.299", filter.RoundDown(decimal.RequireFromString("0.2999999")).String())
+	assert.Equal(t, "0", filter.RoundDown(decimal.RequireFromString("0.0009")).String())
+	assert.Equal(t, "1.2345", QuantityFilter{}.RoundDown(decimal.RequireFromString("1.2345")).String())
+}
+
+// TestQuantityFilter_RoundDown_Property checks that for random quantities and
+// step sizes the rounded quantity is an exact multiple of the step, never
+// above the quantity and less than one step below it.
+func TestQuantityFilter_RoundDown_Property(t *testing.T) {
+	property := func(units uint64, stepUnits uint16, stepExp, qtyExp uint8) bool {
+		step := decimal.New(int64(stepUnits%1000)+1, -int32(stepExp%9))
+		quantity := decimal.New(int64(units%1e12), -int32(qtyExp%12))
+		rounded := QuantityFilter{StepSize: step}.RoundDown(quantity)
+
+		return rounded.Mod(step).IsZero() &&
+			rounded.LessThanOrEqual(quantity) &&
+			quantity.Sub(rounded).LessThan(step) &&
+			SymbolFilters{LotSize: QuantityFilter{StepSize: step}}.ValidateMarketOrder("BTCUSDT", rounded, decimal.Zero) == nil
+	}
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 5000}))
+}
@@ -0,0 +1,312 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"binance-trade-bot-go/internal/clock"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// PaperClient is a paper-trading implementation of the RestClientInterface.
+// Market data (server time, prices, exchange rules) is read from an underlying
+// client, while orders are filled locally against the best bid or ask and
+// settled in a simulated balance ledger. Nothing is ever sent to the exchange.
+type PaperClient struct {
+	market  RestClientInterface
+	logger  *zap.Logger
+	feeRate decimal.Decimal
+	clock   clock.Clock
+
+	mu          sync.Mutex
+	balances    map[string]decimal.Decimal
+	symbols     map[string]SymbolInfo
+	orders      map[int64]*CreateOrderResponse
+	clientIDs   map[string]int64
+	nextOrderID int64
+}
+
+// ensure PaperClient implements the interface
+var _ RestClientInterface = (*PaperClient)(nil)
+
+// NewPaperClient creates a paper-trading client on top of a market data source.
+// The fee rate is deducted from the received asset of every fill, mirroring how
+// Binance charges commission when BNB fee payment is disabled.
+func NewPaperClient(market RestClientInterface, feeRate float64, balances map[string]float64, logger *zap.Logger) *PaperClient {
+	ledger := make(map[string]decimal.Decimal, len(balances))
+	for asset, qty := range balances {
+		// Viper lower-cases map keys, so normalise them back to Binance's asset notation.
+		ledger[strings.ToUpper(asset)] = decimal.NewFromFloat(qty)
+	}
+	if len(ledger) == 0 {
+		logger.Warn("Paper trading enabled without any starting balances, every order will be rejected")
+	}
+	logger.Warn("Paper trading enabled, orders will be simulated", zap.Any("balances", ledger))
+
+	return &PaperClient{
+		market:      market,
+		logger:      logger,
+		feeRate:     decimal.NewFromFloat(feeRate),
+		clock:       clock.Real(),
+		balances:    ledger,
+		orders:      make(map[int64]*CreateOrderResponse),
+		clientIDs:   make(map[string]int64),
+		nextOrderID: 1,
+	}
+}
+
+// SetClock makes the client timestamp orders and account snapshots with c
+// instead of the wall clock.
+func (c *PaperClient) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clk
+}
+
+// GetServerTime delegates to the underlying market data client.
+func (c *PaperClient) GetServerTime(ctx context.Context) (int64, error) {
+	return c.market.GetServerTime(ctx)
+}
+
+// TimeOffset reports the clock offset of the underlying market data client.
+func (c *PaperClient) TimeOffset() time.Duration {
+	if r, ok := c.market.(TimeOffsetReporter); ok {
+		return r.TimeOffset()
+	}
+	return 0
+}
+
+// LastTimeSync reports when the underlying market data client last synced its clock.
+func (c *PaperClient) LastTimeSync() time.Time {
+	if r, ok := c.market.(TimeOffsetReporter); ok {
+		return r.LastTimeSync()
+	}
+	return time.Time{}
+}
+
+// WeightUsage reports the request weight used by the underlying market data client.
+func (c *PaperClient) WeightUsage() WeightUsage {
+	if r, ok := c.market.(WeightUsageReporter); ok {
+		return r.WeightUsage()
+	}
+	return WeightUsage{}
+}
+
+// GetAllTickerPrices delegates to the underlying market data client.
+func (c *PaperClient) GetAllTickerPrices(ctx context.Context) (map[string]string, error) {
+	return c.market.GetAllTickerPrices(ctx)
+}
+
+// GetAllBookTickers delegates to the underlying market data client.
+func (c *PaperClient) GetAllBookTickers(ctx context.Context) (map[string]BookTicker, error) {
+	return c.market.GetAllBookTickers(ctx)
+}
+
+// GetExchangeInfo delegates to the underlying market data client and caches
+// the symbol definitions needed to settle simulated orders.
+func (c *PaperClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfoResponse, error) {
+	info, err := c.market.GetExchangeInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cacheSymbols(info)
+	c.mu.Unlock()
+
+	return info, nil
+}
+
+// cacheSymbols indexes the exchange info by symbol. The caller must hold c.mu.
+func (c *PaperClient) cacheSymbols(info *ExchangeInfoResponse) {
+	c.symbols = make(map[string]SymbolInfo, len(info.Symbols))
+	for _, s := range info.Symbols {
+		c.symbols[s.Symbol] = s
+	}
+}
+
+// symbolInfo returns the definition of a symbol, loading exchange info on first use.
+func (c *PaperClient) symbolInfo(ctx context.Context, symbol string) (SymbolInfo, error) {
+	c.mu.Lock()
+	loaded := c.symbols != nil
+	c.mu.Unlock()
+
+	if !loaded {
+		if _, err := c.GetExchangeInfo(ctx); err != nil {
+			return SymbolInfo{}, fmt.Errorf("failed to load exchange info: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info, ok := c.symbols[symbol]
+	if !ok || info.BaseAsset == "" || info.QuoteAsset == "" {
+		return SymbolInfo{}, fmt.Errorf("unknown symbol %s", symbol)
+	}
+	return info, nil
+}
+
+// CreateOrder fills a MARKET order immediately at the top of the order book and
+// updates the simulated ledger. The order is rejected if the ledger does not
+// hold enough of the asset being spent.
+func (c *PaperClient) CreateOrder(ctx context.Context, symbol, side string, quantity decimal.Decimal, clientOrderID string) (*CreateOrderResponse, error) {
+	if !quantity.IsPositive() {
+		return nil, fmt.Errorf("invalid order quantity %s for %s", quantity, symbol)
+	}
+
+	info, err := c.symbolInfo(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create paper order: %w", err)
+	}
+
+	// Like a real market order, a sell fills at the best bid and a buy at the best ask.
+	tickers, err := c.market.GetAllBookTickers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price for paper order: %w", err)
+	}
+	bookPrice := tickers[symbol].AskPrice
+	if side == OrderSideSell {
+		bookPrice = tickers[symbol].BidPrice
+	}
+	price, err := decimal.NewFromString(bookPrice)
+	if err != nil || !price.IsPositive() {
+		return nil, fmt.Errorf("no valid price available for %s", symbol)
+	}
+
+	quoteQty := quantity.Mul(price)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var commission decimal.Decimal
+	var commissionAsset string
+	switch side {
+	case OrderSideSell:
+		if c.balances[info.BaseAsset].LessThan(quantity) {
+			return nil, fmt.Errorf("insufficient paper balance: have %s %s, need %s: %w", c.balances[info.BaseAsset], info.BaseAsset, quantity, ErrInsufficientBalance)
+		}
+		commission, commissionAsset = quoteQty.Mul(c.feeRate), info.QuoteAsset
+		c.balances[info.BaseAsset] = c.balances[info.BaseAsset].Sub(quantity)
+		c.balances[info.QuoteAsset] = c.balances[info.QuoteAsset].Add(quoteQty.Sub(commission))
+	case OrderSideBuy:
+		if c.balances[info.QuoteAsset].LessThan(quoteQty) {
+			return nil, fmt.Errorf("insufficient paper balance: have %s %s, need %s: %w", c.balances[info.QuoteAsset], info.QuoteAsset, quoteQty, ErrInsufficientBalance)
+		}
+		commission, commissionAsset = quantity.Mul(c.feeRate), info.BaseAsset
+		c.balances[info.QuoteAsset] = c.balances[info.QuoteAsset].Sub(quoteQty)
+		c.balances[info.BaseAsset] = c.balances[info.BaseAsset].Add(quantity.Sub(commission))
+	default:
+		return nil, fmt.Errorf("unsupported order side %q", side)
+	}
+
+	if clientOrderID == "" {
+		clientOrderID = fmt.Sprintf("paper-%d", c.nextOrderID)
+	}
+	order := &CreateOrderResponse{
+		Symbol:              symbol,
+		OrderID:             c.nextOrderID,
+		ClientOrderID:       clientOrderID,
+		TransactTime:        c.clock.Now().UnixMilli(),
+		Price:               "0",
+		OrigQuantity:        quantity.String(),
+		ExecutedQuantity:    quantity.String(),
+		CummulativeQuoteQty: quoteQty.String(),
+		Status:              OrderStatusFilled,
+		Type:                OrderTypeMarket,
+		Side:                side,
+		Fills: []Fill{{
+			Price:           price.String(),
+			Quantity:        quantity.String(),
+			Commission:      commission.String(),
+			CommissionAsset: commissionAsset,
+			TradeID:         c.nextOrderID,
+		}},
+	}
+	c.orders[order.OrderID] = order
+	c.clientIDs[clientOrderID] = order.OrderID
+	c.nextOrderID++
+
+	c.logger.Info("Filled paper order",
+		zap.String("symbol", symbol),
+		zap.String("side", side),
+		zap.Stringer("quantity", quantity),
+		zap.Stringer("price", price),
+	)
+	return order, nil
+}
+
+// GetAccount reports the simulated ledger as account balances.
+// Paper balances are never locked, since every order fills immediately.
+func (c *PaperClient) GetAccount(ctx context.Context) (*AccountResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	account := &AccountResponse{
+		CanTrade:    true,
+		AccountType: "SPOT",
+		UpdateTime:  c.clock.Now().UnixMilli(),
+	}
+	for asset, qty := range c.balances {
+		account.Balances = append(account.Balances, Balance{
+			Asset:  asset,
+			Free:   qty.String(),
+			Locked: "0",
+		})
+	}
+	sort.Slice(account.Balances, func(i, j int) bool { return account.Balances[i].Asset < account.Balances[j].Asset })
+	return account, nil
+}
+
+// GetOrder returns the state of a previously filled paper order.
+func (c *PaperClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.orderStatus(symbol, orderID)
+}
+
+// GetOrderByClientID returns the state of a previously filled paper order by
+// its client order ID.
+func (c *PaperClient) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*OrderResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.orderStatus(symbol, c.clientIDs[clientOrderID])
+}
+
+// orderStatus reports a paper order like GET /order. The caller must hold c.mu.
+func (c *PaperClient) orderStatus(symbol string, orderID int64) (*OrderResponse, error) {
+	order, ok := c.orders[orderID]
+	if !ok || order.Symbol != symbol {
+		return nil, fmt.Errorf("paper order for %s does not exist: %w", symbol, ErrOrderNotFound)
+	}
+	return &OrderResponse{
+		Symbol:              order.Symbol,
+		OrderID:             order.OrderID,
+		ClientOrderID:       order.ClientOrderID,
+		Price:               order.Price,
+		OrigQuantity:        order.OrigQuantity,
+		ExecutedQuantity:    order.ExecutedQuantity,
+		CummulativeQuoteQty: order.CummulativeQuoteQty,
+		Status:              order.Status,
+		Type:                order.Type,
+		Side:                order.Side,
+		Time:                order.TransactTime,
+		UpdateTime:          order.TransactTime,
+	}, nil
+}
+
+// Balances returns a copy of the simulated ledger.
+func (c *PaperClient) Balances() map[string]decimal.Decimal {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	balances := make(map[string]decimal.Decimal, len(c.balances))
+	for asset, qty := range c.balances {
+		balances[asset] = qty
+	}
+	return balances
+}
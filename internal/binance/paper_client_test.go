@@ -0,0 +1,172 @@
+package binance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"binance-trade-bot-go/internal/clock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stubMarket is a static market data source for paper trading tests.
+type stubMarket struct {
+	prices map[string]string
+	books  map[string]BookTicker // defaults to a zero spread around prices
+	info   *ExchangeInfoResponse
+}
+
+func (m *stubMarket) GetServerTime(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *stubMarket) GetAllTickerPrices(ctx context.Context) (map[string]string, error) {
+	return m.prices, nil
+}
+
+func (m *stubMarket) GetAllBookTickers(ctx context.Context) (map[string]BookTicker, error) {
+	if m.books != nil {
+		return m.books, nil
+	}
+	tickers := make(map[string]BookTicker, len(m.prices))
+	for symbol, price := range m.prices {
+		tickers[symbol] = BookTicker{Symbol: symbol, BidPrice: price, AskPrice: price}
+	}
+	return tickers, nil
+}
+
+func (m *stubMarket) GetExchangeInfo(ctx context.Context) (*ExchangeInfoResponse, error) {
+	return m.info, nil
+}
+
+func (m *stubMarket) CreateOrder(ctx context.Context, symbol, side string, quantity decimal.Decimal, clientOrderID string) (*CreateOrderResponse, error) {
+	panic("paper client must never forward orders to the market")
+}
+
+func (m *stubMarket) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderResponse, error) {
+	panic("paper client must never query orders on the market")
+}
+
+func (m *stubMarket) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*OrderResponse, error) {
+	panic("paper client must never query orders on the market")
+}
+
+func (m *stubMarket) GetAccount(ctx context.Context) (*AccountResponse, error) {
+	panic("paper client must never read the real account")
+}
+
+func newStubMarket() *stubMarket {
+	return &stubMarket{
+		prices: map[string]string{"BTCUSDT": "60000", "ETHUSDT": "4000"},
+		info: &ExchangeInfoResponse{Symbols: []SymbolInfo{
+			{Symbol: "BTCUSDT", Status: "TRADING", BaseAsset: "BTC", QuoteAsset: "USDT"},
+			{Symbol: "ETHUSDT", Status: "TRADING", BaseAsset: "ETH", QuoteAsset: "USDT"},
+		}},
+	}
+}
+
+func TestPaperClient_CreateOrder(t *testing.T) {
+	t.Run("Sell and buy settle the ledger with fees", func(t *testing.T) {
+		pc := NewPaperClient(newStubMarket(), 0.001, map[string]float64{"btc": 1}, zap.NewNop())
+
+		sell, err := pc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.RequireFromString("0.5"), "")
+		assert.NoError(t, err)
+		assert.Equal(t, OrderStatusFilled, sell.Status)
+		assert.Equal(t, "0.5", sell.ExecutedQuantity)
+		assert.Equal(t, "30000", sell.CummulativeQuoteQty)
+		assert.Equal(t, []Fill{{Price: "60000", Quantity: "0.5", Commission: "30", CommissionAsset: "USDT", TradeID: sell.OrderID}}, sell.Fills)
+
+		balances := pc.Balances()
+		assert.Equal(t, "0.5", balances["BTC"].String())
+		assert.Equal(t, "29970", balances["USDT"].String())
+
+		buy, err := pc.CreateOrder(context.Background(), "ETHUSDT", OrderSideBuy, decimal.NewFromInt(7), "jump-1-buy")
+		assert.NoError(t, err)
+		assert.Equal(t, "28000", buy.CummulativeQuoteQty)
+		assert.Equal(t, "jump-1-buy", buy.ClientOrderID)
+		assert.NotEqual(t, sell.OrderID, buy.OrderID)
+
+		balances = pc.Balances()
+		assert.Equal(t, "1970", balances["USDT"].String())
+		assert.Equal(t, "6.993", balances["ETH"].String())
+
+		queried, err := pc.GetOrder(context.Background(), "ETHUSDT", buy.OrderID)
+		assert.NoError(t, err)
+		assert.Equal(t, OrderStatusFilled, queried.Status)
+		assert.Equal(t, "7", queried.ExecutedQuantity)
+
+		_, err = pc.GetOrder(context.Background(), "BTCUSDT", buy.OrderID)
+		assert.ErrorIs(t, err, ErrOrderNotFound)
+
+		byClientID, err := pc.GetOrderByClientID(context.Background(), "ETHUSDT", "jump-1-buy")
+		assert.NoError(t, err)
+		assert.Equal(t, buy.OrderID, byClientID.OrderID)
+
+		_, err = pc.GetOrderByClientID(context.Background(), "ETHUSDT", "jump-2-buy")
+		assert.ErrorIs(t, err, ErrOrderNotFound)
+	})
+
+	t.Run("Insufficient balance is rejected", func(t *testing.T) {
+		pc := NewPaperClient(newStubMarket(), 0.001, map[string]float64{"USDT": 100}, zap.NewNop())
+
+		_, err := pc.CreateOrder(context.Background(), "BTCUSDT", OrderSideBuy, decimal.NewFromInt(1), "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "insufficient paper balance")
+		assert.ErrorIs(t, err, ErrInsufficientBalance)
+		assert.Equal(t, "100", pc.Balances()["USDT"].String())
+	})
+
+	t.Run("Unknown symbol is rejected", func(t *testing.T) {
+		pc := NewPaperClient(newStubMarket(), 0.001, map[string]float64{"USDT": 100}, zap.NewNop())
+
+		_, err := pc.CreateOrder(context.Background(), "LTCUSDT", OrderSideBuy, decimal.NewFromInt(1), "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown symbol")
+	})
+}
+
+func TestPaperClient_GetAccount(t *testing.T) {
+	pc := NewPaperClient(newStubMarket(), 0.001, map[string]float64{"usdt": 100, "btc": 0.5}, zap.NewNop())
+
+	account, err := pc.GetAccount(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, account.CanTrade)
+	assert.Equal(t, []Balance{
+		{Asset: "BTC", Free: "0.5", Locked: "0"},
+		{Asset: "USDT", Free: "100", Locked: "0"},
+	}, account.Balances)
+}
+
+func TestPaperClient_CreateOrder_FillsAtBidAndAsk(t *testing.T) {
+	market := newStubMarket()
+	market.books = map[string]BookTicker{"BTCUSDT": {Symbol: "BTCUSDT", BidPrice: "59000", AskPrice: "61000"}}
+	pc := NewPaperClient(market, 0, map[string]float64{"BTC": 1, "USDT": 61000}, zap.NewNop())
+
+	sell, err := pc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "59000", sell.CummulativeQuoteQty)
+
+	buy, err := pc.CreateOrder(context.Background(), "BTCUSDT", OrderSideBuy, decimal.NewFromInt(1), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "61000", buy.CummulativeQuoteQty)
+
+	// The round trip loses the spread
+	assert.Equal(t, "59000", pc.Balances()["USDT"].String())
+}
+
+func TestPaperClient_UsesClock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	pc := NewPaperClient(newStubMarket(), 0.001, map[string]float64{"BTC": 1}, zap.NewNop())
+	pc.SetClock(clock.NewManual(now))
+
+	order, err := pc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "")
+	assert.NoError(t, err)
+	assert.Equal(t, now.UnixMilli(), order.TransactTime)
+
+	account, err := pc.GetAccount(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, now.UnixMilli(), account.UpdateTime)
+}
@@ -5,44 +5,105 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"binance-trade-bot-go/internal/config"
 	"github.com/go-resty/resty/v2"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 )
 
 const (
-	baseURL         = "https://api.binance.com/api/v3"
-	testnetBaseURL  = "https://testnet.binance.vision/api/v3"
-	recvWindow      = "5000" // How long a request is valid in milliseconds
-	OrderTypeMarket = "MARKET"
-	OrderSideBuy    = "BUY"
-	OrderSideSell   = "SELL"
+	baseURL           = "https://api.binance.com/api/v3"
+	testnetBaseURL    = "https://testnet.binance.vision/api/v3"
+	defaultRecvWindow = 5000  // How long a request is valid in milliseconds
+	maxRecvWindow     = 60000 // Largest recvWindow Binance accepts
+
+	defaultRequestTimeout   = 10 * time.Second // Deadline of a single HTTP attempt
+	defaultTimeSyncInterval = 10 * time.Minute // Applies when binance.time_sync_interval is not set
+
+	maxOrderAttempts  = 3 // Placements of an idempotent order with an unknown outcome
+	OrderTypeMarket   = "MARKET"
+	OrderSideBuy      = "BUY"
+	OrderSideSell     = "SELL"
+	OrderRespTypeFull = "FULL"
 )
 
+// Order statuses as reported by Binance.
+const (
+	OrderStatusNew             = "NEW"
+	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
+	OrderStatusFilled          = "FILLED"
+	OrderStatusCanceled        = "CANCELED"
+	OrderStatusRejected        = "REJECTED"
+	OrderStatusExpired         = "EXPIRED"
+	OrderStatusExpiredInMatch  = "EXPIRED_IN_MATCH"
+)
+
+// Symbol statuses as reported by Binance. Only TRADING symbols accept orders.
+const (
+	SymbolStatusTrading = "TRADING"
+	SymbolStatusBreak   = "BREAK"
+	SymbolStatusHalt    = "HALT"
+)
+
+// orderRetryDelay is how long to wait before looking up an order whose
+// placement had an unknown outcome.
+var orderRetryDelay = time.Second
+
 // RestClientInterface defines the interface for the Binance REST API client.
 type RestClientInterface interface {
-	GetServerTime() (int64, error)
-	GetAllTickerPrices() (map[string]string, error)
-	GetExchangeInfo() (*ExchangeInfoResponse, error)
-	CreateOrder(symbol, side string, quantity float64) (*CreateOrderResponse, error)
+	GetServerTime(ctx context.Context) (int64, error)
+	GetAllTickerPrices(ctx context.Context) (map[string]string, error)
+	GetAllBookTickers(ctx context.Context) (map[string]BookTicker, error)
+	GetExchangeInfo(ctx context.Context) (*ExchangeInfoResponse, error)
+	CreateOrder(ctx context.Context, symbol, side string, quantity decimal.Decimal, clientOrderID string) (*CreateOrderResponse, error)
+	GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderResponse, error)
+	GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*OrderResponse, error)
+	GetAccount(ctx context.Context) (*AccountResponse, error)
 }
 
 // RestClient is a client for the Binance REST API.
 // It implements the RestClientInterface.
 type RestClient struct {
-	client    *resty.Client
-	apiKey    string
-	secretKey string
-	logger    *zap.Logger
-	limiter   *rate.Limiter
+	client     *resty.Client
+	apiKey     string
+	secretKey  string
+	logger     *zap.Logger
+	limiter    *rate.Limiter
+	weights    *WeightLimiter
+	recvWindow int64
+	// requestTimeout bounds every HTTP attempt, the caller's context bounds the
+	// whole call including retries.
+	requestTimeout time.Duration
+
+	// timeOffset is the difference between Binance's clock and ours in milliseconds.
+	timeOffset atomic.Int64
+	// lastTimeSync is when timeOffset was last measured, in Unix milliseconds.
+	lastTimeSync atomic.Int64
+}
+
+// TimeOffsetReporter is implemented by clients that compensate for the drift
+// between the local clock and the Binance server clock.
+type TimeOffsetReporter interface {
+	// TimeOffset returns how far the server clock is ahead of the local clock.
+	TimeOffset() time.Duration
+	// LastTimeSync returns when the offset was last measured.
+	LastTimeSync() time.Time
+}
+
+// WeightUsageReporter is implemented by clients that track the request weight
+// and order count limits of Binance.
+type WeightUsageReporter interface {
+	WeightUsage() WeightUsage
 }
 
 // ensure RestClient implements the interface
@@ -65,12 +126,29 @@ func NewRestClient(cfg *config.Binance, logger *zap.Logger) *RestClient {
 	// rate.Limit is requests per second.
 	limiter := rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.RateLimitBurst)
 
+	recvWindow := cfg.RecvWindow
+	if recvWindow <= 0 {
+		recvWindow = defaultRecvWindow
+	} else if recvWindow > maxRecvWindow {
+		logger.Warn("recv_window is above the Binance maximum, capping it", zap.Int64("recv_window", recvWindow))
+		recvWindow = maxRecvWindow
+	}
+
+	requestTimeout := time.Duration(cfg.RequestTimeout) * time.Second
+	if requestTimeout <= 0 {
+		requestTimeout = defaultRequestTimeout
+	}
+
 	return &RestClient{
-		client:    client,
-		apiKey:    cfg.ApiKey,
-		secretKey: cfg.SecretKey,
-		logger:    logger,
-		limiter:   limiter,
+		client:     client,
+		apiKey:     cfg.ApiKey,
+		secretKey:  cfg.SecretKey,
+		logger:     logger,
+		limiter:    limiter,
+		weights:    NewWeightLimiter(cfg.WeightLimit, cfg.OrderLimit10s, cfg.OrderLimit1d, logger),
+		recvWindow: recvWindow,
+
+		requestTimeout: requestTimeout,
 	}
 }
 
@@ -81,18 +159,27 @@ func (c *RestClient) sign(data string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// signParams adds the timestamp, recvWindow and signature to the parameters
+// of a SIGNED endpoint and returns the encoded query string.
+func (c *RestClient) signParams(params url.Values) string {
+	params.Set("timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()+c.timeOffset.Load()))
+	params.Set("recvWindow", strconv.FormatInt(c.recvWindow, 10))
+
+	queryString := params.Encode()
+	return queryString + "&signature=" + c.sign(queryString)
+}
+
 // GetServerTime fetches the current server time from Binance.
 // This is a good endpoint to test connectivity.
-func (c *RestClient) GetServerTime() (int64, error) {
+func (c *RestClient) GetServerTime(ctx context.Context) (int64, error) {
 	type ServerTimeResponse struct {
 		ServerTime int64 `json:"serverTime"`
 	}
 
 	req := c.client.R().
 		SetResult(&ServerTimeResponse{})
-	ctx := context.Background()
 
-	resp, err := c.doRequest(ctx, "GET", "/time", req)
+	resp, err := c.doRequest(ctx, "GET", "/time", req, nil)
 	if err != nil {
 		c.logger.Error("Failed to get server time", zap.Error(err))
 		return 0, fmt.Errorf("failed to get server time: %w", err)
@@ -102,6 +189,64 @@ func (c *RestClient) GetServerTime() (int64, error) {
 	return result.ServerTime, nil
 }
 
+// SyncTime measures the offset between the server clock and the local clock,
+// which is added to the timestamp of every signed request.
+func (c *RestClient) SyncTime(ctx context.Context) error {
+	before := time.Now().UnixMilli()
+	serverTime, err := c.GetServerTime(ctx)
+	if err != nil {
+		return err
+	}
+	after := time.Now().UnixMilli()
+
+	// Assume the server read its clock halfway through the round trip.
+	offset := serverTime - (before+after)/2
+	c.timeOffset.Store(offset)
+	c.lastTimeSync.Store(after)
+	c.logger.Info("Synchronized with server time", zap.Int64("offset_ms", offset))
+	return nil
+}
+
+// RunTimeSync re-measures the clock offset every interval until ctx is cancelled.
+// An interval that is not positive falls back to defaultTimeSyncInterval.
+func (c *RestClient) RunTimeSync(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultTimeSyncInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.SyncTime(ctx); err != nil {
+				c.logger.Warn("Failed to synchronize with server time", zap.Error(err))
+			}
+		}
+	}
+}
+
+// TimeOffset returns how far the server clock is ahead of the local clock.
+func (c *RestClient) TimeOffset() time.Duration {
+	return time.Duration(c.timeOffset.Load()) * time.Millisecond
+}
+
+// LastTimeSync returns when the clock offset was last measured, or the zero
+// time if it never was.
+func (c *RestClient) LastTimeSync() time.Time {
+	ms := c.lastTimeSync.Load()
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// WeightUsage returns the current usage of the Binance request limits.
+func (c *RestClient) WeightUsage() WeightUsage {
+	return c.weights.Usage()
+}
+
 // TickerPrice represents the response for a single ticker price.
 type TickerPrice struct {
 	Symbol string `json:"symbol"`
@@ -109,9 +254,23 @@ type TickerPrice struct {
 }
 
 // doRequest handles the actual request execution with rate limiting and retry logic.
-func (c *RestClient) doRequest(ctx context.Context, method, url string, req *resty.Request) (*resty.Response, error) {
-	var resp *resty.Response
-	var err error
+// Requests to SIGNED endpoints pass their parameters in signed; they are signed
+// again on every attempt, so a retry after a time resync carries a fresh
+// timestamp. The retry policy depends on the Binance error code:
+//   - -1021 (timestamp outside recvWindow) resyncs the clock and retries at once
+//   - -1003 and HTTP 429/418 wait for Retry-After before retrying
+//   - -1013 (filter failure), -2010 (insufficient balance) and other 4xx
+//     responses are returned immediately, they would fail the same way again
+//   - 5xx responses and network errors are retried with exponential backoff,
+//     except for order placement, where they fail with ErrOrderStatusUnknown
+//     because the order may have been placed anyway
+//   - an order request aborted by cancelling ctx also fails with
+//     ErrOrderStatusUnknown, it may have reached the exchange
+//
+// Every attempt is bounded by the request timeout. Cancelling ctx aborts the
+// request in flight as well as any backoff.
+func (c *RestClient) doRequest(ctx context.Context, method, path string, req *resty.Request, signed url.Values) (*resty.Response, error) {
+	var lastErr error
 	const maxRetries = 3
 
 	for i := 0; i < maxRetries; i++ {
@@ -119,35 +278,75 @@ func (c *RestClient) doRequest(ctx context.Context, method, url string, req *res
 		if err := c.limiter.Wait(ctx); err != nil {
 			return nil, fmt.Errorf("rate limiter wait failed: %w", err)
 		}
+		if err := c.weights.Wait(ctx, method, path); err != nil {
+			return nil, err
+		}
 
-		c.logger.Debug("Executing request", zap.String("method", method), zap.String("url", c.client.BaseURL+url))
-		resp, err = req.Execute(method, url)
+		if signed != nil {
+			if method == http.MethodGet || method == http.MethodDelete {
+				req.QueryParam = url.Values{}
+				req.SetQueryString(c.signParams(signed))
+			} else {
+				req.SetBody(c.signParams(signed))
+			}
+		}
+
+		c.logger.Debug("Executing request", zap.String("method", method), zap.String("url", c.client.BaseURL+path))
+		resp, err := c.execute(ctx, method, path, req)
+		if ctx.Err() != nil {
+			// The caller gave up, do not retry. An order may have reached the
+			// exchange before the request was aborted.
+			if isOrderEndpoint(method, path) {
+				return nil, fmt.Errorf("%w: %w", ErrOrderStatusUnknown, ctx.Err())
+			}
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			c.weights.Update(resp.Header())
+		}
 
 		if err == nil && !resp.IsError() {
 			return resp, nil // Success
 		}
 
 		// Analyze error and decide whether to retry
-		shouldRetry := false
 		var retryAfter time.Duration
 
-		if resp != nil {
+		if err != nil {
+			// Network or other client-side errors
+			lastErr = err
+			if isOrderEndpoint(method, path) {
+				return nil, fmt.Errorf("%w: %w", ErrOrderStatusUnknown, err)
+			}
+		} else {
+			apiErr := parseAPIError(resp.StatusCode(), resp.Body())
+			lastErr = apiErr
 			statusCode := resp.StatusCode()
-			if statusCode == http.StatusTooManyRequests || statusCode == 418 { // HTTP 429 or 418
-				shouldRetry = true
-				retryAfterHeader := resp.Header().Get("Retry-After")
-				if seconds, err := strconv.Atoi(retryAfterHeader); err == nil {
+
+			switch {
+			case errors.Is(apiErr, ErrTimestampOutOfSync):
+				c.logger.Warn("Request timestamp rejected, resyncing with server time", zap.Error(apiErr))
+				if err := c.SyncTime(ctx); err != nil {
+					return nil, fmt.Errorf("%w (time resync failed: %v)", apiErr, err)
+				}
+				continue
+			case errors.Is(apiErr, ErrTooManyRequests) || statusCode == http.StatusTooManyRequests || statusCode == http.StatusTeapot:
+				if seconds, err := strconv.Atoi(resp.Header().Get("Retry-After")); err == nil {
 					retryAfter = time.Duration(seconds) * time.Second
 				}
-			} else if statusCode >= 500 { // Server errors
-				shouldRetry = true
+			case statusCode >= 500:
+				// Server errors are transient, but the order may have been executed
+				if isOrderEndpoint(method, path) {
+					return nil, fmt.Errorf("%w: %w", ErrOrderStatusUnknown, apiErr)
+				}
+			default:
+				// Filter failures, insufficient balance and any other request error
+				return nil, apiErr
 			}
-		} else { // Network or other client-side errors
-			shouldRetry = true
 		}
 
-		if !shouldRetry {
-			return nil, fmt.Errorf("request failed with status %s: %s", resp.Status(), resp.String())
+		if i == maxRetries-1 {
+			break
 		}
 
 		// If we should retry, calculate wait time
@@ -159,7 +358,7 @@ func (c *RestClient) doRequest(ctx context.Context, method, url string, req *res
 		c.logger.Warn("Request failed, retrying...",
 			zap.Int("attempt", i+1),
 			zap.Duration("retry_after", retryAfter),
-			zap.Error(err),
+			zap.Error(lastErr),
 		)
 
 		select {
@@ -170,19 +369,28 @@ func (c *RestClient) doRequest(ctx context.Context, method, url string, req *res
 		}
 	}
 
-	return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries, err)
+	return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries, lastErr)
+}
+
+// execute sends a single attempt of a request, bounded by the request timeout.
+func (c *RestClient) execute(ctx context.Context, method, path string, req *resty.Request) (*resty.Response, error) {
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+	return req.SetContext(ctx).Execute(method, path)
 }
 
 // GetAllTickerPrices fetches the latest price for all symbols.
-func (c *RestClient) GetAllTickerPrices() (map[string]string, error) {
+func (c *RestClient) GetAllTickerPrices(ctx context.Context) (map[string]string, error) {
 	var prices []*TickerPrice
 
 	req := c.client.R().
 		SetResult(&prices).
 		SetHeader("Content-Type", "application/json")
-	ctx := context.Background()
 
-	resp, err := c.doRequest(ctx, "GET", "/ticker/price", req)
+	resp, err := c.doRequest(ctx, "GET", "/ticker/price", req, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all ticker prices: %w", err)
 	}
@@ -196,6 +404,37 @@ func (c *RestClient) GetAllTickerPrices() (map[string]string, error) {
 	return priceMap, nil
 }
 
+// BookTicker is the best bid and ask currently on the order book of a symbol.
+type BookTicker struct {
+	Symbol   string `json:"symbol"`
+	BidPrice string `json:"bidPrice"`
+	BidQty   string `json:"bidQty"`
+	AskPrice string `json:"askPrice"`
+	AskQty   string `json:"askQty"`
+}
+
+// GetAllBookTickers fetches the best bid and ask for all symbols.
+func (c *RestClient) GetAllBookTickers(ctx context.Context) (map[string]BookTicker, error) {
+	var tickers []*BookTicker
+
+	req := c.client.R().
+		SetResult(&tickers).
+		SetHeader("Content-Type", "application/json")
+
+	resp, err := c.doRequest(ctx, "GET", "/ticker/bookTicker", req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all book tickers: %w", err)
+	}
+
+	result := resp.Result().(*[]*BookTicker)
+	tickerMap := make(map[string]BookTicker, len(*result))
+	for _, t := range *result {
+		tickerMap[t.Symbol] = *t
+	}
+
+	return tickerMap, nil
+}
+
 // ExchangeInfoResponse represents the full response from the /exchangeInfo endpoint.
 type ExchangeInfoResponse struct {
 	Symbols []SymbolInfo `json:"symbols"`
@@ -203,30 +442,51 @@ type ExchangeInfoResponse struct {
 
 // SymbolInfo contains information about a specific trading symbol.
 type SymbolInfo struct {
-	Symbol  string   `json:"symbol"`
-	Status  string   `json:"status"`
-	Filters []Filter `json:"filters"`
+	Symbol     string   `json:"symbol"`
+	Status     string   `json:"status"`
+	BaseAsset  string   `json:"baseAsset"`
+	QuoteAsset string   `json:"quoteAsset"`
+	Filters    []Filter `json:"filters"`
+}
+
+// IsTrading reports whether the symbol currently accepts orders.
+func (s SymbolInfo) IsTrading() bool {
+	return s.Status == SymbolStatusTrading
 }
 
-// Filter represents a single filter for a symbol.
-// We are interested in the LOT_SIZE filter to get the stepSize.
+// Filter represents a single filter for a symbol. Only the fields of its
+// FilterType are set; use SymbolInfo.ParseFilters to get them as numbers.
 type Filter struct {
 	FilterType string `json:"filterType"`
-	MinQty     string `json:"minQty,omitempty"`
-	MaxQty     string `json:"maxQty,omitempty"`
-	StepSize   string `json:"stepSize,omitempty"`
+
+	// PRICE_FILTER
+	MinPrice string `json:"minPrice,omitempty"`
+	MaxPrice string `json:"maxPrice,omitempty"`
+	TickSize string `json:"tickSize,omitempty"`
+
+	// LOT_SIZE and MARKET_LOT_SIZE
+	MinQty   string `json:"minQty,omitempty"`
+	MaxQty   string `json:"maxQty,omitempty"`
+	StepSize string `json:"stepSize,omitempty"`
+
+	// MIN_NOTIONAL and NOTIONAL
+	MinNotional      string `json:"minNotional,omitempty"`
+	MaxNotional      string `json:"maxNotional,omitempty"`
+	ApplyToMarket    bool   `json:"applyToMarket,omitempty"`    // MIN_NOTIONAL
+	ApplyMinToMarket bool   `json:"applyMinToMarket,omitempty"` // NOTIONAL
+	ApplyMaxToMarket bool   `json:"applyMaxToMarket,omitempty"` // NOTIONAL
+	AvgPriceMins     int    `json:"avgPriceMins,omitempty"`
 }
 
 // GetExchangeInfo fetches exchange trading rules and symbol information.
-func (c *RestClient) GetExchangeInfo() (*ExchangeInfoResponse, error) {
+func (c *RestClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfoResponse, error) {
 	var exchangeInfo ExchangeInfoResponse
 
 	req := c.client.R().
 		SetResult(&exchangeInfo).
 		SetHeader("Content-Type", "application/json")
-	ctx := context.Background()
 
-	resp, err := c.doRequest(ctx, "GET", "/exchangeInfo", req)
+	resp, err := c.doRequest(ctx, "GET", "/exchangeInfo", req, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exchange info: %w", err)
 	}
@@ -248,41 +508,204 @@ type CreateOrderResponse struct {
 	TimeInForce         string `json:"timeInForce"`
 	Type                string `json:"type"`
 	Side                string `json:"side"`
+	Fills               []Fill `json:"fills"`
+}
+
+// Fill is a single execution of an order, as returned with the FULL response type.
+type Fill struct {
+	Price           string `json:"price"`
+	Quantity        string `json:"qty"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	TradeID         int64  `json:"tradeId"`
 }
 
 // CreateOrder places a new order on Binance.
-// For simplicity, this example creates a MARKET order.
-func (c *RestClient) CreateOrder(symbol, side string, quantity float64) (*CreateOrderResponse, error) {
+// For simplicity, this example creates a MARKET order. The FULL response type
+// is requested so the individual fills and their commissions are returned.
+//
+// An order with a client order ID is idempotent: when an attempt fails with an
+// unknown outcome, the order is looked up by its client ID and only sent again
+// if Binance does not know it, so a retry can never trade twice. Without a
+// client order ID such a failure is returned as ErrOrderStatusUnknown.
+func (c *RestClient) CreateOrder(ctx context.Context, symbol, side string, quantity decimal.Decimal, clientOrderID string) (*CreateOrderResponse, error) {
 	params := url.Values{}
 	params.Set("symbol", symbol)
 	params.Set("side", side)
 	params.Set("type", OrderTypeMarket)
-	params.Set("quantity", fmt.Sprintf("%f", quantity))
-	params.Set("timestamp", fmt.Sprintf("%d", time.Now().UnixMilli()))
-	params.Set("recvWindow", recvWindow)
+	// The exact decimal is sent, a float formatted to a fixed precision could
+	// round it off the step size.
+	params.Set("quantity", quantity.String())
+	params.Set("newOrderRespType", OrderRespTypeFull)
+	if clientOrderID != "" {
+		params.Set("newClientOrderId", clientOrderID)
+	}
 
-	queryString := params.Encode()
-	signature := c.sign(queryString)
-	params.Set("signature", signature)
+	var err error
+	for attempt := 1; attempt <= maxOrderAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(orderRetryDelay):
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				// The outcome of the previous attempt stays unknown
+				err = fmt.Errorf("%w: %w", err, ctx.Err())
+				break
+			}
+			existing, lookupErr := c.GetOrderByClientID(ctx, symbol, clientOrderID)
+			if lookupErr == nil {
+				c.logger.Warn("Order was placed by an earlier attempt, not sending it again",
+					zap.String("symbol", symbol),
+					zap.String("clientOrderId", clientOrderID),
+					zap.Int64("orderId", existing.OrderID))
+				return existing.createOrderResponse(), nil
+			}
+			if !errors.Is(lookupErr, ErrOrderNotFound) {
+				err = fmt.Errorf("%w (lookup by client order ID failed: %v)", err, lookupErr)
+				break
+			}
+		}
 
+		var order *CreateOrderResponse
+		order, err = c.submitOrder(ctx, params)
+		if err == nil {
+			c.logger.Info("Successfully created order", zap.Any("order", order))
+			return order, nil
+		}
+		if !errors.Is(err, ErrOrderStatusUnknown) || clientOrderID == "" {
+			break
+		}
+		c.logger.Warn("Order outcome unknown, checking whether it was placed",
+			zap.String("symbol", symbol),
+			zap.String("clientOrderId", clientOrderID),
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+	}
+
+	c.logger.Error("Failed to create order",
+		zap.Error(err),
+		zap.String("symbol", symbol),
+	)
+	return nil, fmt.Errorf("failed to create order: %w", err)
+}
+
+// submitOrder sends a single POST /order request.
+func (c *RestClient) submitOrder(ctx context.Context, params url.Values) (*CreateOrderResponse, error) {
 	req := c.client.R().
 		SetHeader("X-MBX-APIKEY", c.apiKey).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
-		SetBody(params.Encode()).
 		SetResult(&CreateOrderResponse{})
 
-	ctx := context.Background()
+	resp, err := c.doRequest(ctx, "POST", "/order", req, params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result().(*CreateOrderResponse), nil
+}
 
-	resp, err := c.doRequest(ctx, "POST", "/order", req)
+// OrderResponse represents the current state of an order as returned by GET /order.
+type OrderResponse struct {
+	Symbol              string `json:"symbol"`
+	OrderID             int64  `json:"orderId"`
+	ClientOrderID       string `json:"clientOrderId"`
+	Price               string `json:"price"`
+	OrigQuantity        string `json:"origQty"`
+	ExecutedQuantity    string `json:"executedQty"`
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+	Status              string `json:"status"`
+	TimeInForce         string `json:"timeInForce"`
+	Type                string `json:"type"`
+	Side                string `json:"side"`
+	Time                int64  `json:"time"`
+	UpdateTime          int64  `json:"updateTime"`
+}
+
+// GetOrder fetches the current status of an order.
+func (c *RestClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*OrderResponse, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", strconv.FormatInt(orderID, 10))
+
+	req := c.client.R().
+		SetHeader("X-MBX-APIKEY", c.apiKey).
+		SetResult(&OrderResponse{})
+
+	resp, err := c.doRequest(ctx, "GET", "/order", req, params)
 	if err != nil {
-		c.logger.Error("Failed to create order after multiple attempts",
-			zap.Error(err),
-			zap.String("symbol", symbol),
-		)
-		return nil, fmt.Errorf("failed to create order: %w", err)
+		return nil, fmt.Errorf("failed to get order %d for %s: %w", orderID, symbol, err)
+	}
+
+	return resp.Result().(*OrderResponse), nil
+}
+
+// GetOrderByClientID fetches the current status of an order by the client
+// order ID it was placed with. It fails with ErrOrderNotFound if Binance does
+// not know the order.
+func (c *RestClient) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*OrderResponse, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("origClientOrderId", clientOrderID)
+
+	req := c.client.R().
+		SetHeader("X-MBX-APIKEY", c.apiKey).
+		SetResult(&OrderResponse{})
+
+	resp, err := c.doRequest(ctx, "GET", "/order", req, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order %s for %s: %w", clientOrderID, symbol, err)
+	}
+
+	return resp.Result().(*OrderResponse), nil
+}
+
+// createOrderResponse converts an order status into the response its
+// placement would have returned. GET /order reports no fills.
+func (o *OrderResponse) createOrderResponse() *CreateOrderResponse {
+	return &CreateOrderResponse{
+		Symbol:              o.Symbol,
+		OrderID:             o.OrderID,
+		ClientOrderID:       o.ClientOrderID,
+		TransactTime:        o.UpdateTime,
+		Price:               o.Price,
+		OrigQuantity:        o.OrigQuantity,
+		ExecutedQuantity:    o.ExecutedQuantity,
+		CummulativeQuoteQty: o.CummulativeQuoteQty,
+		Status:              o.Status,
+		TimeInForce:         o.TimeInForce,
+		Type:                o.Type,
+		Side:                o.Side,
+	}
+}
+
+// Balance is the free and locked amount of a single asset on the account.
+type Balance struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
+
+// AccountResponse represents the account information returned by GET /account.
+type AccountResponse struct {
+	CanTrade    bool      `json:"canTrade"`
+	AccountType string    `json:"accountType"`
+	UpdateTime  int64     `json:"updateTime"`
+	Balances    []Balance `json:"balances"`
+}
+
+// GetAccount fetches the account information, including all non-zero balances.
+func (c *RestClient) GetAccount(ctx context.Context) (*AccountResponse, error) {
+	params := url.Values{}
+	params.Set("omitZeroBalances", "true")
+
+	req := c.client.R().
+		SetHeader("X-MBX-APIKEY", c.apiKey).
+		SetResult(&AccountResponse{})
+
+	resp, err := c.doRequest(ctx, "GET", "/account", req, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account information: %w", err)
 	}
 
-	result := resp.Result().(*CreateOrderResponse)
-	c.logger.Info("Successfully created order", zap.Any("order", result))
-	return result, nil
+	return resp.Result().(*AccountResponse), nil
 }
@@ -1,14 +1,18 @@
 package binance
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"binance-trade-bot-go/internal/config"
 	"github.com/go-resty/resty/v2"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
@@ -22,11 +26,13 @@ func setupTestServer(handler http.Handler) (*RestClient, *httptest.Server) {
 	logger := zap.NewNop() // Use a no-op logger for tests
 
 	rc := &RestClient{
-		client:    client,
-		apiKey:    "test_api_key",
-		secretKey: "test_secret_key",
-		logger:    logger,
-		limiter:   rate.NewLimiter(rate.Inf, 1), // Allow all requests in tests
+		client:     client,
+		apiKey:     "test_api_key",
+		secretKey:  "test_secret_key",
+		logger:     logger,
+		limiter:    rate.NewLimiter(rate.Inf, 1), // Allow all requests in tests
+		weights:    NewWeightLimiter(0, 0, 0, logger),
+		recvWindow: defaultRecvWindow,
 	}
 
 	return rc, server
@@ -49,7 +55,7 @@ func TestGetServerTime(t *testing.T) {
 		defer server.Close()
 
 		// Act
-		serverTime, err := rc.GetServerTime()
+		serverTime, err := rc.GetServerTime(context.Background())
 
 		// Assert
 		assert.NoError(t, err)
@@ -68,7 +74,7 @@ func TestGetServerTime(t *testing.T) {
 		defer server.Close()
 
 		// Act
-		serverTime, err := rc.GetServerTime()
+		serverTime, err := rc.GetServerTime(context.Background())
 
 		// Assert
 		assert.Error(t, err)
@@ -101,3 +107,437 @@ func TestNewRestClient(t *testing.T) {
 		assert.Equal(t, cfg.SecretKey, rc.secretKey)
 	})
 }
+
+func TestGetAllBookTickers(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ticker/bookTicker", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"symbol":"BTCUSDT","bidPrice":"59990.00","bidQty":"1.5","askPrice":"60010.00","askQty":"2.0"},
+			{"symbol":"ETHUSDT","bidPrice":"3899.50","bidQty":"10","askPrice":"3900.50","askQty":"12"}
+		]`))
+	})
+
+	rc, server := setupTestServer(handler)
+	defer server.Close()
+
+	// Act
+	tickers, err := rc.GetAllBookTickers(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, tickers, 2)
+	assert.Equal(t, BookTicker{Symbol: "BTCUSDT", BidPrice: "59990.00", BidQty: "1.5", AskPrice: "60010.00", AskQty: "2.0"}, tickers["BTCUSDT"])
+	assert.Equal(t, "3900.50", tickers["ETHUSDT"].AskPrice)
+}
+
+func TestGetOrder(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/order", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "test_api_key", r.Header.Get("X-MBX-APIKEY"))
+		query := r.URL.Query()
+		assert.Equal(t, "BTCUSDT", query.Get("symbol"))
+		assert.Equal(t, "42", query.Get("orderId"))
+		assert.NotEmpty(t, query.Get("timestamp"))
+		assert.NotEmpty(t, query.Get("signature"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"symbol":"BTCUSDT","orderId":42,"status":"FILLED","executedQty":"0.5","cummulativeQuoteQty":"30000"}`))
+	})
+
+	rc, server := setupTestServer(handler)
+	defer server.Close()
+
+	// Act
+	order, err := rc.GetOrder(context.Background(), "BTCUSDT", 42)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, OrderStatusFilled, order.Status)
+	assert.Equal(t, "0.5", order.ExecutedQuantity)
+	assert.Equal(t, "30000", order.CummulativeQuoteQty)
+}
+
+func TestGetAccount(t *testing.T) {
+	// Arrange
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/account", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "test_api_key", r.Header.Get("X-MBX-APIKEY"))
+		query := r.URL.Query()
+		assert.NotEmpty(t, query.Get("timestamp"))
+		assert.NotEmpty(t, query.Get("signature"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"canTrade":true,"accountType":"SPOT","balances":[{"asset":"BTC","free":"0.25","locked":"0.05"}]}`))
+	})
+
+	rc, server := setupTestServer(handler)
+	defer server.Close()
+
+	// Act
+	account, err := rc.GetAccount(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, account.CanTrade)
+	assert.Equal(t, []Balance{{Asset: "BTC", Free: "0.25", Locked: "0.05"}}, account.Balances)
+}
+
+func TestDoRequest_RetryPolicy(t *testing.T) {
+	t.Run("Filter failure is returned without retrying", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1013,"msg":"Filter failure: LOT_SIZE"}`))
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		_, err := rc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.RequireFromString("0.000001"), "")
+
+		assert.ErrorIs(t, err, ErrFilterFailure)
+		var apiErr *APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, "Filter failure: LOT_SIZE", apiErr.Message)
+			assert.Equal(t, http.StatusBadRequest, apiErr.HTTPStatus)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Order is never retried on insufficient balance", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-2010,"msg":"Account has insufficient balance for requested action."}`))
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		_, err := rc.CreateOrder(context.Background(), "BTCUSDT", OrderSideBuy, decimal.NewFromInt(1), "")
+
+		assert.ErrorIs(t, err, ErrInsufficientBalance)
+		assert.NotErrorIs(t, err, ErrFilterFailure)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Cancelled context stops the backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := rc.GetAllTickerPrices(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Hanging attempt times out and is retried", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"serverTime": 42}`))
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+		rc.requestTimeout = 50 * time.Millisecond
+
+		serverTime, err := rc.GetServerTime(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), serverTime)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Timestamp outside recvWindow resyncs time and re-signs", func(t *testing.T) {
+		serverAhead := int64(10000)
+		var timestamps []int64
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/time" {
+				_, _ = fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().UnixMilli()+serverAhead)
+				return
+			}
+			assert.NoError(t, r.ParseForm())
+			ts, _ := strconv.ParseInt(r.PostForm.Get("timestamp"), 10, 64)
+			timestamps = append(timestamps, ts)
+			if len(timestamps) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"symbol":"BTCUSDT","orderId":1,"status":"FILLED"}`))
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		start := time.Now()
+		order, err := rc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, OrderStatusFilled, order.Status)
+		assert.Less(t, time.Since(start), time.Second, "a time resync must not back off")
+		if assert.Len(t, timestamps, 2) {
+			assert.InDelta(t, serverAhead, timestamps[1]-timestamps[0], 1000)
+		}
+		assert.InDelta(t, serverAhead, rc.timeOffset.Load(), 1000)
+	})
+
+	t.Run("Too many requests waits and retries", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"code":-1003,"msg":"Too many requests."}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"serverTime":1}`))
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		serverTime, err := rc.GetServerTime(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), serverTime)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Non JSON error bodies are kept as the message", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`blocked by WAF`))
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		_, err := rc.GetAllTickerPrices(context.Background())
+
+		var apiErr *APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, 0, apiErr.Code)
+			assert.Equal(t, "blocked by WAF", apiErr.Message)
+		}
+	})
+}
+
+func TestSyncTime(t *testing.T) {
+	// Arrange: the server clock runs 7 seconds ahead of ours
+	serverAhead := int64(7000)
+	var timeCalls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/time":
+			timeCalls.Add(1)
+			_, _ = fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().UnixMilli()+serverAhead)
+		case "/account":
+			query := r.URL.Query()
+			ts, _ := strconv.ParseInt(query.Get("timestamp"), 10, 64)
+			assert.InDelta(t, time.Now().UnixMilli()+serverAhead, ts, 1000)
+			assert.Equal(t, "10000", query.Get("recvWindow"))
+			_, _ = w.Write([]byte(`{"canTrade":true}`))
+		}
+	})
+
+	rc, server := setupTestServer(handler)
+	defer server.Close()
+	rc.recvWindow = 10000
+	assert.True(t, rc.LastTimeSync().IsZero())
+
+	// Act
+	err := rc.SyncTime(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.InDelta(t, serverAhead, rc.TimeOffset().Milliseconds(), 1000)
+	assert.WithinDuration(t, time.Now(), rc.LastTimeSync(), time.Second)
+	_, err = rc.GetAccount(context.Background())
+	assert.NoError(t, err)
+
+	t.Run("Periodic resync", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go rc.RunTimeSync(ctx, 10*time.Millisecond)
+
+		assert.Eventually(t, func() bool { return timeCalls.Load() >= 3 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("Unset interval falls back to the default", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			rc.RunTimeSync(ctx, 0)
+		}()
+		cancel()
+		<-done
+	})
+}
+
+func TestNewRestClient_RecvWindow(t *testing.T) {
+	assert.Equal(t, int64(defaultRecvWindow), NewRestClient(&config.Binance{}, zap.NewNop()).recvWindow)
+	assert.Equal(t, int64(20000), NewRestClient(&config.Binance{RecvWindow: 20000}, zap.NewNop()).recvWindow)
+	assert.Equal(t, int64(maxRecvWindow), NewRestClient(&config.Binance{RecvWindow: 90000}, zap.NewNop()).recvWindow)
+}
+
+func TestDoRequest_TracksRequestWeight(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "321")
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	rc, server := setupTestServer(handler)
+	defer server.Close()
+
+	_, err := rc.GetAllBookTickers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 321, rc.WeightUsage().UsedWeight)
+
+	// In dry-run mode the paper client reports the weight its market data used
+	pc := NewPaperClient(rc, 0.001, nil, zap.NewNop())
+	assert.Equal(t, 321, pc.WeightUsage().UsedWeight)
+}
+
+func TestCreateOrder_Idempotent(t *testing.T) {
+	defer func(delay time.Duration) { orderRetryDelay = delay }(orderRetryDelay)
+	orderRetryDelay = time.Millisecond
+
+	// newServer fails the first order with a 503 and answers lookups with lookup.
+	newServer := func(t *testing.T, lookup func(w http.ResponseWriter)) (*RestClient, *atomic.Int32, *atomic.Int32) {
+		var posts, lookups atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.Method {
+			case http.MethodPost:
+				assert.NoError(t, r.ParseForm())
+				assert.Equal(t, "jump-1-sell", r.PostForm.Get("newClientOrderId"))
+				if posts.Add(1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, _ = w.Write([]byte(`{"symbol":"BTCUSDT","orderId":2,"clientOrderId":"jump-1-sell","status":"FILLED","executedQty":"1"}`))
+			case http.MethodGet:
+				lookups.Add(1)
+				assert.Equal(t, "jump-1-sell", r.URL.Query().Get("origClientOrderId"))
+				lookup(w)
+			}
+		})
+		rc, server := setupTestServer(handler)
+		t.Cleanup(server.Close)
+		return rc, &posts, &lookups
+	}
+
+	t.Run("Order placed by a failed attempt is not sent again", func(t *testing.T) {
+		rc, posts, lookups := newServer(t, func(w http.ResponseWriter) {
+			_, _ = w.Write([]byte(`{"symbol":"BTCUSDT","orderId":1,"clientOrderId":"jump-1-sell","status":"FILLED","executedQty":"1","cummulativeQuoteQty":"60000","updateTime":1234}`))
+		})
+
+		order, err := rc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "jump-1-sell")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), order.OrderID)
+		assert.Equal(t, "60000", order.CummulativeQuoteQty)
+		assert.Equal(t, int64(1234), order.TransactTime)
+		assert.Equal(t, int32(1), posts.Load())
+		assert.Equal(t, int32(1), lookups.Load())
+	})
+
+	t.Run("Unknown order is sent again with the same client order ID", func(t *testing.T) {
+		rc, posts, lookups := newServer(t, func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-2013,"msg":"Order does not exist."}`))
+		})
+
+		order, err := rc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "jump-1-sell")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), order.OrderID)
+		assert.Equal(t, int32(2), posts.Load())
+		assert.Equal(t, int32(1), lookups.Load())
+	})
+
+	t.Run("Failed lookup gives up without sending the order again", func(t *testing.T) {
+		rc, posts, _ := newServer(t, func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":-1100,"msg":"Illegal characters found in parameter."}`))
+		})
+
+		_, err := rc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "jump-1-sell")
+
+		assert.ErrorIs(t, err, ErrOrderStatusUnknown)
+		assert.Equal(t, int32(1), posts.Load())
+	})
+
+	t.Run("Order without client order ID is never retried blindly", func(t *testing.T) {
+		var posts atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			posts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		_, err := rc.CreateOrder(context.Background(), "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "")
+
+		assert.ErrorIs(t, err, ErrOrderStatusUnknown)
+		assert.Equal(t, int32(1), posts.Load())
+	})
+
+	t.Run("Order cancelled in flight has an unknown outcome", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var posts atomic.Int32
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, r.ParseForm())
+			posts.Add(1)
+			// The order reaches the exchange, then the caller gives up
+			cancel()
+			<-r.Context().Done()
+		})
+		rc, server := setupTestServer(handler)
+		defer server.Close()
+
+		_, err := rc.CreateOrder(ctx, "BTCUSDT", OrderSideSell, decimal.NewFromInt(1), "jump-1-sell")
+
+		assert.ErrorIs(t, err, ErrOrderStatusUnknown)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(1), posts.Load())
+	})
+}
+
+func TestCreateOrder_SendsExactQuantity(t *testing.T) {
+	var quantity string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		quantity = r.PostForm.Get("quantity")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"symbol":"ETHUSDT","orderId":1,"status":"FILLED"}`))
+	})
+	rc, server := setupTestServer(handler)
+	defer server.Close()
+
+	// 0.1 + 0.2 is 0.30000000000000004 in float64
+	qty := decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+	_, err := rc.CreateOrder(context.Background(), "ETHUSDT", OrderSideBuy, qty, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.3", quantity)
+}
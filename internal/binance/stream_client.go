@@ -0,0 +1,319 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"binance-trade-bot-go/internal/config"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	streamBaseURL        = "wss://stream.binance.com:9443"
+	testnetStreamBaseURL = "wss://stream.testnet.binance.vision"
+
+	bookTickerStream = "!bookTicker"
+	miniTickerStream = "!miniTicker@arr"
+
+	defaultStreamStaleAfter = 10 * time.Second
+	// Binance closes every connection after 24 hours, reconnect a little earlier.
+	defaultStreamMaxLifetime = 23*time.Hour + 50*time.Minute
+	streamReconnectDelay     = time.Second
+	streamMaxReconnectDelay  = time.Minute
+)
+
+// ErrStreamStale is returned when the stream has not delivered data recently
+// enough for its cached prices to be trusted.
+var ErrStreamStale = errors.New("market data stream is stale")
+
+// SnapshotSource provides complete price lists to seed the stream cache with,
+// typically the REST client.
+type SnapshotSource interface {
+	GetAllTickerPrices(ctx context.Context) (map[string]string, error)
+	GetAllBookTickers(ctx context.Context) (map[string]BookTicker, error)
+}
+
+// StreamClient maintains an in-memory price cache fed by the Binance combined
+// WebSocket streams for all book tickers and all mini tickers. It reconnects
+// automatically when the connection drops and before Binance's 24 hour limit.
+// Its GetAllTickerPrices and GetAllBookTickers methods mirror the REST client,
+// but fail with ErrStreamStale when the corresponding stream has gone quiet.
+type StreamClient struct {
+	url            string
+	logger         *zap.Logger
+	snapshot       SnapshotSource
+	dialer         *websocket.Dialer
+	staleAfter     time.Duration
+	maxLifetime    time.Duration
+	reconnectDelay time.Duration
+
+	mu            sync.RWMutex
+	prices        map[string]string
+	books         map[string]BookTicker
+	pricesUpdated time.Time
+	booksUpdated  time.Time
+	connected     bool
+}
+
+// NewStreamClient creates a stream client for the configured network.
+// Call Run to connect and start filling the cache.
+func NewStreamClient(cfg *config.Binance, logger *zap.Logger) *StreamClient {
+	base := streamBaseURL
+	if cfg.Testnet {
+		base = testnetStreamBaseURL
+	}
+	staleAfter := time.Duration(cfg.StreamStaleAfter) * time.Second
+	if staleAfter <= 0 {
+		staleAfter = defaultStreamStaleAfter
+	}
+	return newStreamClient(base, staleAfter, logger)
+}
+
+func newStreamClient(base string, staleAfter time.Duration, logger *zap.Logger) *StreamClient {
+	return &StreamClient{
+		url:            fmt.Sprintf("%s/stream?streams=%s/%s", base, bookTickerStream, miniTickerStream),
+		logger:         logger.Named("stream"),
+		dialer:         websocket.DefaultDialer,
+		staleAfter:     staleAfter,
+		maxLifetime:    defaultStreamMaxLifetime,
+		reconnectDelay: streamReconnectDelay,
+		prices:         make(map[string]string),
+		books:          make(map[string]BookTicker),
+	}
+}
+
+// SetSnapshotSource makes the client seed its cache from src every time it
+// connects. The streams only push symbols whose price changed, so without a
+// snapshot a quiet symbol is missing from an otherwise fresh cache.
+func (s *StreamClient) SetSnapshotSource(src SnapshotSource) {
+	s.snapshot = src
+}
+
+// Run connects to the stream and keeps it connected until ctx is cancelled.
+func (s *StreamClient) Run(ctx context.Context) {
+	delay := s.reconnectDelay
+	for {
+		start := time.Now()
+		err := s.connectAndRead(ctx)
+		if ctx.Err() != nil {
+			s.logger.Info("Market data stream stopped")
+			return
+		}
+
+		s.mu.Lock()
+		s.connected = false
+		s.mu.Unlock()
+
+		if err == nil {
+			// Planned reconnect before the connection lifetime runs out.
+			delay = s.reconnectDelay
+			continue
+		}
+
+		// Back off while connections keep failing right away.
+		if time.Since(start) > streamMaxReconnectDelay {
+			delay = s.reconnectDelay
+		}
+		s.logger.Warn("Market data stream disconnected, reconnecting", zap.Error(err), zap.Duration("delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		if delay *= 2; delay > streamMaxReconnectDelay {
+			delay = streamMaxReconnectDelay
+		}
+	}
+}
+
+// connectAndRead reads one connection until it fails, ctx is cancelled or the
+// maximum lifetime is reached. It returns nil only for the planned reconnect.
+func (s *StreamClient) connectAndRead(ctx context.Context) error {
+	conn, _, err := s.dialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", s.url, err)
+	}
+	s.logger.Info("Connected to market data stream", zap.String("url", s.url))
+
+	// Seed after connecting, so the updates buffered meanwhile are applied on top.
+	if err := s.seed(ctx); err != nil {
+		conn.Close()
+		return err
+	}
+
+	s.mu.Lock()
+	s.connected = true
+	s.mu.Unlock()
+
+	expired := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		lifetime := time.NewTimer(s.maxLifetime)
+		defer lifetime.Stop()
+		select {
+		case <-ctx.Done():
+		case <-lifetime.C:
+			s.logger.Info("Market data stream reached its maximum lifetime, reconnecting")
+			close(expired)
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	for {
+		// Both streams push at least once a second, so a silent connection is dead.
+		_ = conn.SetReadDeadline(time.Now().Add(s.staleAfter))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-expired:
+				return nil
+			default:
+				return fmt.Errorf("failed to read from market data stream: %w", err)
+			}
+		}
+		if err := s.handleMessage(message); err != nil {
+			s.logger.Warn("Ignoring malformed stream message", zap.Error(err))
+		}
+	}
+}
+
+// seed fills the cache with the prices and book tickers of every symbol from
+// the snapshot source, if there is one.
+func (s *StreamClient) seed(ctx context.Context) error {
+	if s.snapshot == nil {
+		return nil
+	}
+	prices, err := s.snapshot.GetAllTickerPrices(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to seed stream prices: %w", err)
+	}
+	books, err := s.snapshot.GetAllBookTickers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to seed stream book tickers: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for symbol, price := range prices {
+		s.prices[symbol] = price
+	}
+	for symbol, book := range books {
+		s.books[symbol] = book
+	}
+	s.pricesUpdated = time.Now()
+	s.booksUpdated = s.pricesUpdated
+	return nil
+}
+
+// streamMessage is the envelope of a combined stream message.
+type streamMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// wsBookTicker is a single !bookTicker event. All four price and quantity keys
+// are declared because encoding/json matches keys case-insensitively.
+type wsBookTicker struct {
+	Symbol   string `json:"s"`
+	BidPrice string `json:"b"`
+	BidQty   string `json:"B"`
+	AskPrice string `json:"a"`
+	AskQty   string `json:"A"`
+}
+
+// wsMiniTicker is a single entry of a !miniTicker@arr event.
+type wsMiniTicker struct {
+	Symbol     string `json:"s"`
+	ClosePrice string `json:"c"`
+}
+
+// handleMessage updates the cache from a combined stream message.
+func (s *StreamClient) handleMessage(message []byte) error {
+	var msg streamMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return err
+	}
+
+	switch msg.Stream {
+	case bookTickerStream:
+		var t wsBookTicker
+		if err := json.Unmarshal(msg.Data, &t); err != nil {
+			return fmt.Errorf("invalid book ticker: %w", err)
+		}
+		s.mu.Lock()
+		s.books[t.Symbol] = BookTicker{Symbol: t.Symbol, BidPrice: t.BidPrice, BidQty: t.BidQty, AskPrice: t.AskPrice, AskQty: t.AskQty}
+		s.booksUpdated = time.Now()
+		s.mu.Unlock()
+	case miniTickerStream:
+		var tickers []wsMiniTicker
+		if err := json.Unmarshal(msg.Data, &tickers); err != nil {
+			return fmt.Errorf("invalid mini tickers: %w", err)
+		}
+		s.mu.Lock()
+		for _, t := range tickers {
+			s.prices[t.Symbol] = t.ClosePrice
+		}
+		s.pricesUpdated = time.Now()
+		s.mu.Unlock()
+	default:
+		return fmt.Errorf("unexpected stream %q", msg.Stream)
+	}
+	return nil
+}
+
+// GetAllTickerPrices returns the last price of every symbol seen on the mini ticker stream.
+func (s *StreamClient) GetAllTickerPrices(ctx context.Context) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if err := s.checkFresh(s.pricesUpdated); err != nil {
+		return nil, err
+	}
+	prices := make(map[string]string, len(s.prices))
+	for symbol, price := range s.prices {
+		prices[symbol] = price
+	}
+	return prices, nil
+}
+
+// GetAllBookTickers returns the best bid and ask of every symbol seen on the book ticker stream.
+func (s *StreamClient) GetAllBookTickers(ctx context.Context) (map[string]BookTicker, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if err := s.checkFresh(s.booksUpdated); err != nil {
+		return nil, err
+	}
+	books := make(map[string]BookTicker, len(s.books))
+	for symbol, book := range s.books {
+		books[symbol] = book
+	}
+	return books, nil
+}
+
+// checkFresh fails if the stream is down or was last updated too long ago.
+// The caller must hold s.mu.
+func (s *StreamClient) checkFresh(updated time.Time) error {
+	if !s.connected {
+		return fmt.Errorf("%w: not connected", ErrStreamStale)
+	}
+	if updated.IsZero() {
+		return fmt.Errorf("%w: no data received yet", ErrStreamStale)
+	}
+	if age := time.Since(updated); age > s.staleAfter {
+		return fmt.Errorf("%w: last update %s ago", ErrStreamStale, age.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// Connected reports whether the stream currently has an open connection.
+func (s *StreamClient) Connected() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.connected
+}
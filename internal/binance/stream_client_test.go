@@ -0,0 +1,175 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testBookTickerMessage = `{"stream":"!bookTicker","data":{"u":400900217,"s":"BTCUSDT","b":"59990.00","B":"1.5","a":"60010.00","A":"2.0"}}`
+	testMiniTickerMessage = `{"stream":"!miniTicker@arr","data":[{"e":"24hrMiniTicker","E":123456789,"s":"BTCUSDT","c":"60000.00","o":"59000.00"},{"e":"24hrMiniTicker","E":123456789,"s":"ETHUSDT","c":"4000.00","o":"3900.00"}]}`
+)
+
+// setupStreamServer starts a WebSocket server that runs serve for every
+// connection and returns a stream client pointed at it.
+func setupStreamServer(t *testing.T, serve func(conn *websocket.Conn)) (*StreamClient, *atomic.Int32) {
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/stream", r.URL.Path)
+		assert.Equal(t, "!bookTicker/!miniTicker@arr", r.URL.Query().Get("streams"))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		connections.Add(1)
+		serve(conn)
+	}))
+	// Cleanups run in reverse, so the server outlives the client started after it.
+	t.Cleanup(server.Close)
+
+	sc := newStreamClient("ws"+strings.TrimPrefix(server.URL, "http"), 200*time.Millisecond, zap.NewNop())
+	sc.reconnectDelay = 10 * time.Millisecond
+	return sc, &connections
+}
+
+// runStream runs the client until the test ends.
+func runStream(t *testing.T, sc *StreamClient) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sc.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// keepSending writes the test messages until the connection is closed.
+func keepSending(conn *websocket.Conn) {
+	for {
+		for _, msg := range []string{testBookTickerMessage, testMiniTickerMessage} {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStreamClient_CachesPrices(t *testing.T) {
+	sc, _ := setupStreamServer(t, keepSending)
+
+	_, err := sc.GetAllTickerPrices(context.Background())
+	assert.ErrorIs(t, err, ErrStreamStale)
+
+	runStream(t, sc)
+
+	require.Eventually(t, func() bool {
+		_, err := sc.GetAllBookTickers(context.Background())
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	books, err := sc.GetAllBookTickers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, BookTicker{Symbol: "BTCUSDT", BidPrice: "59990.00", BidQty: "1.5", AskPrice: "60010.00", AskQty: "2.0"}, books["BTCUSDT"])
+
+	require.Eventually(t, func() bool {
+		_, err := sc.GetAllTickerPrices(context.Background())
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	prices, err := sc.GetAllTickerPrices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"BTCUSDT": "60000.00", "ETHUSDT": "4000.00"}, prices)
+	assert.True(t, sc.Connected())
+}
+
+func TestStreamClient_SeedsFromSnapshot(t *testing.T) {
+	sc, _ := setupStreamServer(t, keepSending)
+	// BNBUSDT never changes, so the stream alone would never deliver it
+	market := newStubMarket()
+	market.prices = map[string]string{"BTCUSDT": "59000.00", "BNBUSDT": "500.00"}
+	sc.SetSnapshotSource(market)
+	runStream(t, sc)
+
+	require.Eventually(t, func() bool {
+		prices, err := sc.GetAllTickerPrices(context.Background())
+		return err == nil && prices["BTCUSDT"] == "60000.00"
+	}, time.Second, 5*time.Millisecond)
+
+	prices, err := sc.GetAllTickerPrices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"BTCUSDT": "60000.00", "ETHUSDT": "4000.00", "BNBUSDT": "500.00"}, prices)
+	books, err := sc.GetAllBookTickers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "500.00", books["BNBUSDT"].AskPrice)
+}
+
+func TestStreamClient_GoesStaleWhenQuiet(t *testing.T) {
+	// Send a single update and then keep the connection open without data.
+	sc, _ := setupStreamServer(t, func(conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(testMiniTickerMessage))
+		time.Sleep(time.Second)
+	})
+	runStream(t, sc)
+
+	require.Eventually(t, func() bool {
+		_, err := sc.GetAllTickerPrices(context.Background())
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		_, err := sc.GetAllTickerPrices(context.Background())
+		return errors.Is(err, ErrStreamStale)
+	}, time.Second, 10*time.Millisecond)
+
+	// The book ticker stream never delivered anything
+	_, err := sc.GetAllBookTickers(context.Background())
+	assert.ErrorIs(t, err, ErrStreamStale)
+}
+
+func TestStreamClient_ReconnectsAfterDisconnect(t *testing.T) {
+	// Every connection delivers one update and is then dropped by the server.
+	sc, connections := setupStreamServer(t, func(conn *websocket.Conn) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(testMiniTickerMessage))
+	})
+	runStream(t, sc)
+
+	assert.Eventually(t, func() bool { return connections.Load() >= 3 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestStreamClient_ReconnectsBeforeMaxLifetime(t *testing.T) {
+	sc, connections := setupStreamServer(t, keepSending)
+	sc.maxLifetime = 50 * time.Millisecond
+	runStream(t, sc)
+
+	assert.Eventually(t, func() bool { return connections.Load() >= 3 }, 2*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		_, err := sc.GetAllBookTickers(context.Background())
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestStreamClient_IgnoresMalformedMessages(t *testing.T) {
+	sc := newStreamClient("ws://unused", time.Second, zap.NewNop())
+
+	assert.Error(t, sc.handleMessage([]byte(`not json`)))
+	assert.Error(t, sc.handleMessage([]byte(`{"stream":"btcusdt@trade","data":{}}`)))
+	assert.NoError(t, sc.handleMessage([]byte(testBookTickerMessage)))
+	assert.Equal(t, "1.5", sc.books["BTCUSDT"].BidQty)
+}
@@ -0,0 +1,267 @@
+package binance
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWeightLimit   = 6000   // Request weight per minute
+	defaultOrderLimit10s = 100    // Orders per 10 seconds
+	defaultOrderLimit1d  = 200000 // Orders per day
+
+	// weightHeadroom is the share of a limit the client uses before pausing, so
+	// requests it cannot see, e.g. from the stream or another bot on the same IP,
+	// do not push it over the edge.
+	weightHeadroom = 0.9
+
+	usedWeightHeader = "X-Mbx-Used-Weight-1m"
+	orderCountPrefix = "X-Mbx-Order-Count-"
+)
+
+// endpointWeights are the request weights of the endpoints the client calls,
+// keyed by method and path. Endpoints that are not listed weigh 1.
+var endpointWeights = map[string]int{
+	"GET /time":              1,
+	"GET /ticker/price":      4, // without symbol
+	"GET /ticker/bookTicker": 4, // without symbol
+	"GET /exchangeInfo":      20,
+	"POST /order":            1,
+	"GET /order":             4,
+	"GET /account":           20,
+}
+
+// metrics publishes the current usage of the Binance limits under
+// /debug/vars, which expvar registers on the default HTTP mux.
+var metrics = expvar.NewMap("binance")
+
+// usageWindow counts usage of one limit over a fixed interval aligned to the
+// clock, the way Binance counts it.
+type usageWindow struct {
+	name     string
+	limit    int
+	interval time.Duration
+	used     int
+	start    time.Time
+}
+
+// roll starts a new window once the current one has passed.
+func (w *usageWindow) roll(now time.Time) {
+	if start := now.Truncate(w.interval); start.After(w.start) {
+		w.start, w.used = start, 0
+	}
+}
+
+// wait returns how long to wait before n more units fit in the window.
+func (w *usageWindow) wait(n int, now time.Time) time.Duration {
+	w.roll(now)
+	if float64(w.used+n) <= float64(w.limit)*weightHeadroom {
+		return 0
+	}
+	return w.start.Add(w.interval).Sub(now)
+}
+
+// WeightUsage is a snapshot of the usage of the Binance limits.
+type WeightUsage struct {
+	UsedWeight    int `json:"used_weight_1m"`
+	WeightLimit   int `json:"weight_limit_1m"`
+	OrderCount10s int `json:"order_count_10s"`
+	OrderCount1d  int `json:"order_count_1d"`
+}
+
+// WeightLimiter keeps track of the request weight and order count limits of
+// Binance. Requests reserve their weight before being sent and wait for the
+// next window when it would exceed the limit. The usage reported in the
+// response headers replaces the local count, keeping it in sync with the
+// server's view.
+type WeightLimiter struct {
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu        sync.Mutex
+	weight    usageWindow
+	orders    usageWindow
+	ordersDay usageWindow
+}
+
+// NewWeightLimiter creates a limiter for the given limits. Non-positive limits
+// fall back to the Binance defaults. A limit too low for the heaviest request
+// to ever fit under the headroom is raised to the lowest one it fits, since
+// Wait would otherwise block that request for good.
+func NewWeightLimiter(weightLimit, orderLimit10s, orderLimit1d int, logger *zap.Logger) *WeightLimiter {
+	if weightLimit <= 0 {
+		weightLimit = defaultWeightLimit
+	}
+	if orderLimit10s <= 0 {
+		orderLimit10s = defaultOrderLimit10s
+	}
+	if orderLimit1d <= 0 {
+		orderLimit1d = defaultOrderLimit1d
+	}
+	weightLimit = atLeastFitting(weightLimit, maxEndpointWeight(), "weight_limit", logger)
+	orderLimit10s = atLeastFitting(orderLimit10s, 1, "order_limit_10s", logger)
+	orderLimit1d = atLeastFitting(orderLimit1d, 1, "order_limit_1d", logger)
+	return &WeightLimiter{
+		logger:    logger,
+		now:       time.Now,
+		weight:    usageWindow{name: "request weight", limit: weightLimit, interval: time.Minute},
+		orders:    usageWindow{name: "order count (10s)", limit: orderLimit10s, interval: 10 * time.Second},
+		ordersDay: usageWindow{name: "order count (1d)", limit: orderLimit1d, interval: 24 * time.Hour},
+	}
+}
+
+// maxEndpointWeight returns the weight of the heaviest endpoint the client calls.
+func maxEndpointWeight() int {
+	heaviest := 1
+	for _, weight := range endpointWeights {
+		heaviest = max(heaviest, weight)
+	}
+	return heaviest
+}
+
+// atLeastFitting returns limit, raised if needed so that a request of the given
+// weight fits under the headroom of an empty window.
+func atLeastFitting(limit, weight int, name string, logger *zap.Logger) int {
+	lowest := int(math.Ceil(float64(weight) / weightHeadroom))
+	if limit >= lowest {
+		return limit
+	}
+	logger.Warn("Binance limit is too low for the heaviest request, raising it",
+		zap.String("limit", name),
+		zap.Int("configured", limit),
+		zap.Int("used", lowest))
+	return lowest
+}
+
+// endpointWeight returns the request weight of an endpoint.
+func endpointWeight(method, path string) int {
+	if weight, ok := endpointWeights[method+" "+path]; ok {
+		return weight
+	}
+	return 1
+}
+
+// isOrderEndpoint reports whether a request places an order and counts
+// towards the order limits.
+func isOrderEndpoint(method, path string) bool {
+	return method == http.MethodPost && path == "/order"
+}
+
+// Wait blocks until the request fits within the limits and reserves its usage.
+func (l *WeightLimiter) Wait(ctx context.Context, method, path string) error {
+	weight := endpointWeight(method, path)
+	order := isOrderEndpoint(method, path)
+
+	for {
+		l.mu.Lock()
+		now := l.now()
+		window, wait := &l.weight, l.weight.wait(weight, now)
+		if order && wait == 0 {
+			if wait = l.orders.wait(1, now); wait > 0 {
+				window = &l.orders
+			} else if wait = l.ordersDay.wait(1, now); wait > 0 {
+				window = &l.ordersDay
+			}
+		}
+		if wait == 0 {
+			l.weight.used += weight
+			if order {
+				l.orders.used++
+				l.ordersDay.used++
+			}
+			l.publish()
+			l.mu.Unlock()
+			return nil
+		}
+		used, limit := window.used, window.limit
+		name := window.name
+		l.mu.Unlock()
+
+		l.logger.Warn("Approaching Binance limit, pausing requests",
+			zap.String("limit", name),
+			zap.Int("used", used),
+			zap.Int("max", limit),
+			zap.Duration("wait", wait))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %s limit: %w", name, ctx.Err())
+		}
+	}
+}
+
+// Update syncs the usage with the X-MBX-USED-WEIGHT-1M and X-MBX-ORDER-COUNT-*
+// headers of a response.
+func (l *WeightLimiter) Update(header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if used, err := strconv.Atoi(header.Get(usedWeightHeader)); err == nil {
+		l.weight.roll(now)
+		l.weight.used = used
+	}
+	for key, values := range header {
+		if !strings.HasPrefix(key, orderCountPrefix) || len(values) == 0 {
+			continue
+		}
+		count, err := strconv.Atoi(values[0])
+		if err != nil {
+			continue
+		}
+		switch strings.ToUpper(strings.TrimPrefix(key, orderCountPrefix)) {
+		case "10S":
+			l.orders.roll(now)
+			l.orders.used = count
+		case "1D":
+			l.ordersDay.roll(now)
+			l.ordersDay.used = count
+		}
+	}
+	l.publish()
+}
+
+// Usage returns the current usage of the limits.
+func (l *WeightLimiter) Usage() WeightUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.usage()
+}
+
+// usage builds the usage snapshot. The caller must hold l.mu.
+func (l *WeightLimiter) usage() WeightUsage {
+	now := l.now()
+	l.weight.roll(now)
+	l.orders.roll(now)
+	l.ordersDay.roll(now)
+	return WeightUsage{
+		UsedWeight:    l.weight.used,
+		WeightLimit:   l.weight.limit,
+		OrderCount10s: l.orders.used,
+		OrderCount1d:  l.ordersDay.used,
+	}
+}
+
+// publish exports the current usage as metrics. The caller must hold l.mu.
+func (l *WeightLimiter) publish() {
+	usage := l.usage()
+	setMetric("used_weight_1m", usage.UsedWeight)
+	setMetric("weight_limit_1m", usage.WeightLimit)
+	setMetric("order_count_10s", usage.OrderCount10s)
+	setMetric("order_count_1d", usage.OrderCount1d)
+}
+
+func setMetric(name string, value int) {
+	v := new(expvar.Int)
+	v.Set(int64(value))
+	metrics.Set(name, v)
+}
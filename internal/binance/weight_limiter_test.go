@@ -0,0 +1,109 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestWeightLimiter returns a limiter whose clock is controlled by the test.
+func newTestWeightLimiter(weightLimit, orderLimit10s int, now *time.Time) *WeightLimiter {
+	l := NewWeightLimiter(weightLimit, orderLimit10s, 0, zap.NewNop())
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestWeightLimiter_ReservesEndpointWeights(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 10, 0, time.UTC)
+	l := newTestWeightLimiter(0, 0, &now)
+
+	require.NoError(t, l.Wait(context.Background(), http.MethodGet, "/exchangeInfo"))
+	require.NoError(t, l.Wait(context.Background(), http.MethodGet, "/ticker/price"))
+	require.NoError(t, l.Wait(context.Background(), http.MethodPost, "/order"))
+	require.NoError(t, l.Wait(context.Background(), http.MethodGet, "/unknown"))
+
+	assert.Equal(t, WeightUsage{UsedWeight: 26, WeightLimit: defaultWeightLimit, OrderCount10s: 1, OrderCount1d: 1}, l.Usage())
+
+	// The counts start over with the next window
+	now = now.Add(time.Minute)
+	assert.Equal(t, WeightUsage{UsedWeight: 0, WeightLimit: defaultWeightLimit, OrderCount10s: 0, OrderCount1d: 1}, l.Usage())
+}
+
+func TestWeightLimiter_SyncsWithHeaders(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 10, 0, time.UTC)
+	l := newTestWeightLimiter(0, 0, &now)
+	require.NoError(t, l.Wait(context.Background(), http.MethodGet, "/account"))
+
+	header := http.Header{}
+	header.Set("X-MBX-USED-WEIGHT-1M", "1234")
+	header.Set("X-MBX-ORDER-COUNT-10S", "7")
+	header.Set("X-MBX-ORDER-COUNT-1D", "150")
+	l.Update(header)
+
+	assert.Equal(t, WeightUsage{UsedWeight: 1234, WeightLimit: defaultWeightLimit, OrderCount10s: 7, OrderCount1d: 150}, l.Usage())
+	assert.Equal(t, "1234", metrics.Get("used_weight_1m").String())
+
+	// Malformed headers are ignored
+	header.Set("X-MBX-USED-WEIGHT-1M", "lots")
+	l.Update(header)
+	assert.Equal(t, 1234, l.Usage().UsedWeight)
+}
+
+func TestWeightLimiter_PausesNearTheLimit(t *testing.T) {
+	// 50ms before the next minute starts
+	now := time.Date(2024, 5, 1, 12, 0, 59, 950*int(time.Millisecond), time.UTC)
+	l := newTestWeightLimiter(100, 0, &now)
+
+	header := http.Header{}
+	header.Set("X-MBX-USED-WEIGHT-1M", "88")
+	l.Update(header)
+
+	// Weight 1 still fits in the 90% headroom, weight 4 does not
+	require.NoError(t, l.Wait(context.Background(), http.MethodGet, "/time"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.Wait(ctx, http.MethodGet, "/ticker/price")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The request goes through once the next minute starts
+	now = now.Add(50 * time.Millisecond)
+	require.NoError(t, l.Wait(context.Background(), http.MethodGet, "/ticker/price"))
+	assert.Equal(t, 4, l.Usage().UsedWeight)
+}
+
+func TestWeightLimiter_PausesOrdersAtTheOrderLimit(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 9, 0, time.UTC)
+	l := newTestWeightLimiter(0, 10, &now)
+
+	for i := 0; i < 9; i++ {
+		require.NoError(t, l.Wait(context.Background(), http.MethodPost, "/order"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(ctx, http.MethodPost, "/order"), context.DeadlineExceeded)
+	// Queries are not orders and are not held back
+	assert.NoError(t, l.Wait(context.Background(), http.MethodGet, "/order"))
+}
+
+func TestWeightLimiter_RaisesLimitsTooLowForARequest(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 10, 0, time.UTC)
+	l := newTestWeightLimiter(10, 1, &now)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// 20 does not fit under 90% of 10, the limit is raised to 23 so it does.
+	require.NoError(t, l.Wait(ctx, http.MethodGet, "/exchangeInfo"))
+	assert.Equal(t, WeightUsage{UsedWeight: 20, WeightLimit: 23}, l.Usage())
+
+	// An order does not fit under 90% of 1, the order limits are raised to 2.
+	now = now.Add(time.Minute)
+	require.NoError(t, l.Wait(ctx, http.MethodPost, "/order"))
+	assert.Equal(t, WeightUsage{UsedWeight: 1, WeightLimit: 23, OrderCount10s: 1, OrderCount1d: 1}, l.Usage())
+}
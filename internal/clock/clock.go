@@ -0,0 +1,162 @@
+// Package clock abstracts the passage of time, so that the trader runs on the
+// wall clock while tests and simulations move a manual clock step by step.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and schedules timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After sends the time on the returned channel once d has passed.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a ticker that ticks every d. It panics if d is not positive.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, see time.Ticker.
+type Ticker interface {
+	// C returns the channel the ticks are delivered on.
+	C() <-chan time.Time
+	// Stop turns the ticker off. It does not close the channel.
+	Stop()
+}
+
+// Real returns the wall clock.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return realTicker{time.NewTicker(d)} }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+// Manual is a clock that only moves when Advance or Set is called. Timers and
+// tickers whose time has come fire during that call, in order. Like
+// time.Ticker, a ticker drops the ticks its receiver is not ready for.
+type Manual struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter is a pending After or ticker of a Manual clock.
+type waiter struct {
+	at     time.Time
+	period time.Duration // Zero for After
+	ch     chan time.Time
+}
+
+// ensure Manual implements the interface
+var _ Clock = (*Manual)(nil)
+
+// NewManual creates a manual clock set to start.
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+// Now returns the time the clock was last set to.
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// After sends the time on the returned channel once the clock has been
+// advanced by d. A non-positive d fires immediately.
+func (m *Manual) After(d time.Duration) <-chan time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := &waiter{at: m.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- m.now
+		return w.ch
+	}
+	m.waiters = append(m.waiters, w)
+	return w.ch
+}
+
+// NewTicker returns a ticker that ticks every time the clock passes another d.
+func (m *Manual) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w := &waiter{at: m.now.Add(d), period: d, ch: make(chan time.Time, 1)}
+	m.waiters = append(m.waiters, w)
+	return &manualTicker{clock: m, w: w}
+}
+
+// Advance moves the clock forward by d.
+func (m *Manual) Advance(d time.Duration) {
+	m.Set(m.Now().Add(d))
+}
+
+// Set moves the clock to t and fires every timer and ticker due by then. The
+// clock never moves backwards, an earlier t is ignored.
+func (m *Manual) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t.Before(m.now) {
+		return
+	}
+	m.now = t
+
+	sort.SliceStable(m.waiters, func(i, j int) bool { return m.waiters[i].at.Before(m.waiters[j].at) })
+	pending := m.waiters[:0]
+	for _, w := range m.waiters {
+		if w.at.After(t) {
+			pending = append(pending, w)
+			continue
+		}
+		select {
+		case w.ch <- w.at:
+		default:
+		}
+		if w.period > 0 {
+			for !w.at.After(t) {
+				w.at = w.at.Add(w.period)
+			}
+			pending = append(pending, w)
+		}
+	}
+	m.waiters = pending
+}
+
+// Waiters returns how many timers and tickers are pending, so a test can wait
+// for a goroutine to start waiting before it advances the clock.
+func (m *Manual) Waiters() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.waiters)
+}
+
+func (m *Manual) remove(w *waiter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, other := range m.waiters {
+		if other == w {
+			m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+type manualTicker struct {
+	clock *Manual
+	w     *waiter
+}
+
+func (t *manualTicker) C() <-chan time.Time { return t.w.ch }
+func (t *manualTicker) Stop()               { t.clock.remove(t.w) }
@@ -0,0 +1,62 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// received returns the value waiting on ch, or the zero time if there is none.
+func received(ch <-chan time.Time) time.Time {
+	select {
+	case t := <-ch:
+		return t
+	default:
+		return time.Time{}
+	}
+}
+
+func TestManual_After(t *testing.T) {
+	clock := NewManual(start)
+	ch := clock.After(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	assert.Zero(t, received(ch))
+	assert.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Minute), received(ch))
+	assert.Zero(t, clock.Waiters())
+
+	assert.Equal(t, start.Add(time.Minute), received(clock.After(0)))
+}
+
+func TestManual_Ticker(t *testing.T) {
+	clock := NewManual(start)
+	ticker := clock.NewTicker(10 * time.Second)
+
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, start.Add(10*time.Second), received(ticker.C()))
+
+	// Ticks the receiver is not ready for are dropped.
+	clock.Advance(35 * time.Second)
+	assert.Equal(t, start.Add(20*time.Second), received(ticker.C()))
+	assert.Zero(t, received(ticker.C()))
+
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, start.Add(50*time.Second), received(ticker.C()))
+
+	ticker.Stop()
+	clock.Advance(time.Hour)
+	assert.Zero(t, received(ticker.C()))
+	assert.Zero(t, clock.Waiters())
+}
+
+func TestManual_NeverMovesBackwards(t *testing.T) {
+	clock := NewManual(start)
+	clock.Set(start.Add(-time.Hour))
+	assert.Equal(t, start, clock.Now())
+}
@@ -17,11 +17,19 @@ type Config struct {
 
 // Binance holds the configuration for the Binance API.
 type Binance struct {
-	ApiKey         string  `mapstructure:"apiKey"`
-	SecretKey      string  `mapstructure:"secretKey"`
-	Testnet        bool    `mapstructure:"testnet"`
-	RateLimit      float64 `mapstructure:"rate_limit"`
-	RateLimitBurst int     `mapstructure:"rate_limit_burst"`
+	ApiKey           string  `mapstructure:"apiKey"`
+	SecretKey        string  `mapstructure:"secretKey"`
+	Testnet          bool    `mapstructure:"testnet"`
+	RateLimit        float64 `mapstructure:"rate_limit"`
+	RateLimitBurst   int     `mapstructure:"rate_limit_burst"`
+	RecvWindow       int64   `mapstructure:"recv_window"`        // milliseconds
+	RequestTimeout   int     `mapstructure:"request_timeout"`    // seconds
+	TimeSyncInterval int     `mapstructure:"time_sync_interval"` // seconds
+	StreamEnabled    bool    `mapstructure:"stream_enabled"`
+	StreamStaleAfter int     `mapstructure:"stream_stale_after"` // seconds
+	WeightLimit      int     `mapstructure:"weight_limit"`       // request weight per minute
+	OrderLimit10s    int     `mapstructure:"order_limit_10s"`
+	OrderLimit1d     int     `mapstructure:"order_limit_1d"`
 }
 
 // Server holds the configuration for the web server.
@@ -37,16 +45,24 @@ type Database struct {
 
 // Trading holds the configuration for the trading logic.
 type Trading struct {
-	Bridge       string   `mapstructure:"bridge"`
-	TradePairs   []string `mapstructure:"trade_pairs"`
-	Quantity     float64  `mapstructure:"quantity"`
-	FeeRate      float64  `mapstructure:"fee_rate"`
-	DryRun       bool     `mapstructure:"dry_run"`
-	TickInterval int      `mapstructure:"tick_interval"`
-	ScoutMargin  float64  `mapstructure:"scout_margin"`
-	Strategy     string   `mapstructure:"strategy"`
-	Name         string   `mapstructure:"name"`
-	ApiPort      int      `mapstructure:"api_port"`
+	Bridge                      string                 `mapstructure:"bridge"`
+	TradePairs                  []string               `mapstructure:"trade_pairs"`
+	StartingCoin                string                 `mapstructure:"starting_coin"`
+	Quantity                    float64                `mapstructure:"quantity"`
+	FeeRate                     float64                `mapstructure:"fee_rate"`
+	ProfitModel                 string                 `mapstructure:"profit_model"`
+	DryRun                      bool                   `mapstructure:"dry_run"`
+	PaperBalances               map[string]float64     `mapstructure:"paper_balances"`
+	TickInterval                int                    `mapstructure:"tick_interval"`
+	ScoutMargin                 float64                `mapstructure:"scout_margin"`
+	Strategy                    string                 `mapstructure:"strategy"`
+	Name                        string                 `mapstructure:"name"`
+	ApiPort                     int                    `mapstructure:"api_port"`
+	OrderFillTimeout            int                    `mapstructure:"order_fill_timeout"` // seconds
+	JumpMaxBuyAttempts          int                    `mapstructure:"jump_max_buy_attempts"`
+	PortfolioSyncInterval       int                    `mapstructure:"portfolio_sync_interval"`        // seconds
+	ExchangeInfoRefreshInterval int                    `mapstructure:"exchange_info_refresh_interval"` // seconds
+	StrategyParams              map[string]interface{} `mapstructure:"strategy_params"`                // Parameters of the selected strategy
 }
 
 // Logger holds the configuration for the logger.
@@ -66,8 +82,16 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	// Set default values
-	viper.SetDefault("binance.rate_limit", 20)      // requests per second
-	viper.SetDefault("binance.rate_limit_burst", 5) // burst size
+	viper.SetDefault("binance.rate_limit", 20)          // requests per second
+	viper.SetDefault("binance.rate_limit_burst", 5)     // burst size
+	viper.SetDefault("binance.recv_window", 5000)       // milliseconds
+	viper.SetDefault("binance.request_timeout", 10)     // seconds
+	viper.SetDefault("binance.time_sync_interval", 600) // seconds
+	viper.SetDefault("binance.weight_limit", 6000)      // request weight per minute
+	viper.SetDefault("binance.order_limit_10s", 100)
+	viper.SetDefault("binance.order_limit_1d", 200000)
+	viper.SetDefault("trading.profit_model", "last_price")
+	viper.SetDefault("trading.strategy", "Default")
 
 	err = viper.ReadInConfig()
 	if err != nil {
@@ -8,49 +8,54 @@ import (
 	"gorm.io/gorm"
 )
 
-// NewDatabase creates a new database connection and performs auto-migration.
+// NewDatabase creates a new database connection, applies pending migrations and seeds the coins.
 func NewDatabase(cfg *config.Config) (*gorm.DB, error) {
 	db, err := gorm.Open(sqlite.Open(cfg.Database.DSN), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	// Auto-migrate the schema
-	err = AutoMigrate(db, cfg)
-	if err != nil {
+	if err := Migrate(db); err != nil {
 		return nil, err
 	}
 
-	return db, nil
-}
-
-// AutoMigrate drops existing tables, creates new ones, and populates initial data.
-func AutoMigrate(db *gorm.DB, cfg *config.Config) error {
-	// Drop all existing tables to ensure a clean state
-	if err := db.Migrator().DropTable(&models.Trade{}, &models.Pair{}, &models.Coin{}); err != nil {
-		// We can ignore "not found" errors, but fail on others
-		if err.Error() != "table not found" {
-			return fmt.Errorf("failed to drop tables: %w", err)
-		}
+	if err := SeedCoins(db, cfg); err != nil {
+		return nil, err
 	}
 
-	// Create new tables based on the current models
-	if err := db.AutoMigrate(&models.Trade{}, &models.Pair{}, &models.Coin{}); err != nil {
-		return fmt.Errorf("failed to auto-migrate database: %w", err)
-	}
+	return db, nil
+}
 
-	// Populate the 'coins' table from the config
+// SeedCoins makes the 'coins' table match the configured trade pairs.
+// It is idempotent: missing coins are created, configured coins are enabled and
+// coins that are no longer configured are disabled rather than deleted, so their
+// history and balances are kept.
+func SeedCoins(db *gorm.DB, cfg *config.Config) error {
 	allCoins := make(map[string]struct{})
 	for _, pair := range cfg.Trading.TradePairs {
 		allCoins[pair] = struct{}{}
 	}
 
-	for coinSymbol := range allCoins {
-		coin := models.Coin{Symbol: coinSymbol, Enabled: true}
-		if err := db.FirstOrCreate(&coin, models.Coin{Symbol: coinSymbol}).Error; err != nil {
-			return fmt.Errorf("failed to populate coin '%s': %w", coinSymbol, err)
+	return db.Transaction(func(tx *gorm.DB) error {
+		for coinSymbol := range allCoins {
+			coin := models.Coin{Symbol: coinSymbol, Enabled: true}
+			if err := tx.FirstOrCreate(&coin, models.Coin{Symbol: coinSymbol}).Error; err != nil {
+				return fmt.Errorf("failed to populate coin '%s': %w", coinSymbol, err)
+			}
+			if !coin.Enabled {
+				if err := tx.Model(&coin).Update("enabled", true).Error; err != nil {
+					return fmt.Errorf("failed to enable coin '%s': %w", coinSymbol, err)
+				}
+			}
 		}
-	}
 
-	return nil
+		disable := tx.Model(&models.Coin{}).Where("enabled = ?", true)
+		if len(cfg.Trading.TradePairs) > 0 {
+			disable = disable.Where("symbol NOT IN ?", cfg.Trading.TradePairs)
+		}
+		if err := disable.Update("enabled", false).Error; err != nil {
+			return fmt.Errorf("failed to disable unconfigured coins: %w", err)
+		}
+		return nil
+	})
 }
@@ -0,0 +1,385 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a single versioned schema change.
+// Up must be idempotent enough to run against a database created by the
+// legacy AutoMigrate code, which has tables but no recorded version.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // optional
+}
+
+// SchemaVersion records every migration that has been applied to the database.
+type SchemaVersion struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"not null"`
+	AppliedAt time.Time
+}
+
+// TableName overrides the table name used by SchemaVersion.
+func (SchemaVersion) TableName() string {
+	return "schema_version"
+}
+
+// migrations is the ordered list of all schema changes.
+// Each migration declares its own snapshot of the models it touches, so that
+// later changes to the models package never alter what an old migration does.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create coins, pairs and trades",
+		Up: func(tx *gorm.DB) error {
+			type Coin struct {
+				gorm.Model
+				Symbol   string  `gorm:"uniqueIndex"`
+				Quantity float64 `gorm:"not null"`
+				Enabled  bool    `gorm:"default:true"`
+			}
+			type Pair struct {
+				gorm.Model
+				FromCoinSymbol string  `gorm:"uniqueIndex:idx_from_to"`
+				ToCoinSymbol   string  `gorm:"uniqueIndex:idx_from_to"`
+				Ratio          float64 `gorm:"not null"`
+				MinQty         float64 `gorm:"not null"`
+			}
+			type Trade struct {
+				gorm.Model
+				Symbol        string
+				Type          string
+				Price         float64
+				Quantity      float64
+				QuoteQuantity float64
+				Timestamp     int64
+				IsSimulation  bool
+				Profit        float64
+			}
+			return tx.AutoMigrate(&Coin{}, &Pair{}, &Trade{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("trades", "pairs", "coins")
+		},
+	},
+	{
+		Version: 2,
+		Name:    "record order fills on trades",
+		Up: func(tx *gorm.DB) error {
+			type Trade struct {
+				OrderID         int64
+				Commission      float64
+				CommissionAsset string
+			}
+			return tx.AutoMigrate(&Trade{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Trade struct{}
+			for _, column := range []string{"order_id", "commission", "commission_asset"} {
+				if err := tx.Migrator().DropColumn(&Trade{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create jumps",
+		Up: func(tx *gorm.DB) error {
+			type Jump struct {
+				gorm.Model
+				FromCoinSymbol string
+				ToCoinSymbol   string
+				BridgeSymbol   string
+				State          string `gorm:"index"`
+				FromQuantity   float64
+				BridgeQuantity float64
+				BuyCoinSymbol  string
+				BuyQuantity    float64
+				BuyAttempts    int
+				SellOrderID    int64
+				BuyOrderID     int64
+				Profit         float64
+				Error          string
+			}
+			return tx.AutoMigrate(&Jump{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("jumps")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create current coin history",
+		Up: func(tx *gorm.DB) error {
+			type CurrentCoin struct {
+				gorm.Model
+				CoinSymbol string `gorm:"not null"`
+			}
+			return tx.Table("current_coin_history").AutoMigrate(&CurrentCoin{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("current_coin_history")
+		},
+	},
+	{
+		Version: 5,
+		Name:    "track locked coin balances",
+		Up: func(tx *gorm.DB) error {
+			type Coin struct {
+				Locked float64 `gorm:"not null;default:0"`
+			}
+			return tx.AutoMigrate(&Coin{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Coin struct{}
+			return tx.Migrator().DropColumn(&Coin{}, "locked")
+		},
+	},
+	{
+		Version: 6,
+		Name:    "store amounts as exact decimals",
+		// Prices and quantities are stored as decimal strings, a REAL column would
+		// round them to the nearest float. SQLite cannot change a column type in
+		// place, so AutoMigrate rebuilds the tables and recreates their indexes.
+		Up: func(tx *gorm.DB) error {
+			type Coin struct {
+				gorm.Model
+				Symbol   string `gorm:"uniqueIndex"`
+				Quantity string `gorm:"type:text;not null;default:0"`
+				Locked   string `gorm:"type:text;not null;default:0"`
+			}
+			type Pair struct {
+				gorm.Model
+				FromCoinSymbol string `gorm:"uniqueIndex:idx_from_to"`
+				ToCoinSymbol   string `gorm:"uniqueIndex:idx_from_to"`
+				MinQty         string `gorm:"type:text;not null;default:0"`
+			}
+			type Trade struct {
+				gorm.Model
+				Price         string `gorm:"type:text"`
+				Quantity      string `gorm:"type:text"`
+				QuoteQuantity string `gorm:"type:text"`
+				Commission    string `gorm:"type:text"`
+			}
+			type Jump struct {
+				gorm.Model
+				State          string `gorm:"index"`
+				FromQuantity   string `gorm:"type:text"`
+				BridgeQuantity string `gorm:"type:text"`
+				BuyQuantity    string `gorm:"type:text"`
+			}
+			return tx.AutoMigrate(&Coin{}, &Pair{}, &Trade{}, &Jump{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Coin struct {
+				gorm.Model
+				Symbol   string  `gorm:"uniqueIndex"`
+				Quantity float64 `gorm:"not null"`
+				Locked   float64 `gorm:"not null;default:0"`
+			}
+			type Pair struct {
+				gorm.Model
+				FromCoinSymbol string  `gorm:"uniqueIndex:idx_from_to"`
+				ToCoinSymbol   string  `gorm:"uniqueIndex:idx_from_to"`
+				MinQty         float64 `gorm:"not null"`
+			}
+			type Trade struct {
+				gorm.Model
+				Price         float64
+				Quantity      float64
+				QuoteQuantity float64
+				Commission    float64
+			}
+			type Jump struct {
+				gorm.Model
+				State          string `gorm:"index"`
+				FromQuantity   float64
+				BridgeQuantity float64
+				BuyQuantity    float64
+			}
+			return tx.AutoMigrate(&Coin{}, &Pair{}, &Trade{}, &Jump{})
+		},
+	},
+	{
+		Version: 7,
+		Name:    "track pair trading status",
+		Up: func(tx *gorm.DB) error {
+			type Pair struct {
+				Enabled bool `gorm:"not null;default:true"`
+			}
+			return tx.AutoMigrate(&Pair{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Pair struct{}
+			return tx.Migrator().DropColumn(&Pair{}, "enabled")
+		},
+	},
+	{
+		Version: 8,
+		Name:    "record why pairs are disabled",
+		Up: func(tx *gorm.DB) error {
+			type Pair struct {
+				DisabledReason string
+			}
+			return tx.AutoMigrate(&Pair{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Pair struct{}
+			return tx.Migrator().DropColumn(&Pair{}, "disabled_reason")
+		},
+	},
+	{
+		Version: 9,
+		Name:    "record direct jumps",
+		Up: func(tx *gorm.DB) error {
+			type Jump struct {
+				DirectSymbol string
+			}
+			return tx.AutoMigrate(&Jump{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Jump struct{}
+			return tx.Migrator().DropColumn(&Jump{}, "direct_symbol")
+		},
+	},
+	{
+		Version: 10,
+		Name:    "store pair ratios as exact decimals",
+		// Like version 6, the table is rebuilt with the ratio as a decimal string.
+		Up: func(tx *gorm.DB) error {
+			type Pair struct {
+				gorm.Model
+				FromCoinSymbol string `gorm:"uniqueIndex:idx_from_to"`
+				ToCoinSymbol   string `gorm:"uniqueIndex:idx_from_to"`
+				Ratio          string `gorm:"type:text;not null;default:0"`
+			}
+			return tx.AutoMigrate(&Pair{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Pair struct {
+				gorm.Model
+				FromCoinSymbol string  `gorm:"uniqueIndex:idx_from_to"`
+				ToCoinSymbol   string  `gorm:"uniqueIndex:idx_from_to"`
+				Ratio          float64 `gorm:"not null"`
+			}
+			return tx.AutoMigrate(&Pair{})
+		},
+	},
+	{
+		Version: 11,
+		Name:    "record the route of multi-hop jumps",
+		Up: func(tx *gorm.DB) error {
+			type Jump struct {
+				RouteID string `gorm:"index"`
+			}
+			return tx.AutoMigrate(&Jump{})
+		},
+		Down: func(tx *gorm.DB) error {
+			type Jump struct {
+				RouteID string `gorm:"index"`
+			}
+			if err := tx.Migrator().DropIndex(&Jump{}, "RouteID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&Jump{}, "route_id")
+		},
+	},
+}
+
+// Migrate applies all pending migrations in version order.
+// Each migration runs in its own transaction together with its version record,
+// so a failed migration leaves the database at the last successful version.
+func Migrate(db *gorm.DB) error {
+	return migrate(db, migrations)
+}
+
+func migrate(db *gorm.DB, all []Migration) error {
+	if err := db.AutoMigrate(&SchemaVersion{}); err != nil {
+		return fmt.Errorf("failed to create schema_version table: %w", err)
+	}
+
+	current, err := CurrentVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range sorted(all) {
+		if m.Version <= current {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaVersion{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Rollback reverts applied migrations, newest first, until the schema is at
+// the target version. It fails if a migration on the way has no Down step.
+func Rollback(db *gorm.DB, target int) error {
+	return rollback(db, migrations, target)
+}
+
+func rollback(db *gorm.DB, all []Migration, target int) error {
+	current, err := CurrentVersion(db)
+	if err != nil {
+		return err
+	}
+
+	ordered := sorted(all)
+	for i := len(ordered) - 1; i >= 0; i-- {
+		m := ordered[i]
+		if m.Version > current || m.Version <= target {
+			continue
+		}
+		if m.Down == nil {
+			return fmt.Errorf("migration %d (%s) cannot be rolled back", m.Version, m.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaVersion{}, m.Version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to roll back migration %d (%s): %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// CurrentVersion returns the highest applied migration version, or 0 for a fresh database.
+func CurrentVersion(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&SchemaVersion{}) {
+		return 0, nil
+	}
+	var version int
+	if err := db.Model(&SchemaVersion{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// sorted returns a copy of the migrations ordered by version.
+func sorted(all []Migration) []Migration {
+	ordered := make([]Migration, len(all))
+	copy(ordered, all)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Version < ordered[j].Version })
+	return ordered
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"testing"
+
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrate_PreservesDataAcrossRuns(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, Migrate(db))
+
+	require.NoError(t, db.Create(&models.Trade{Symbol: "BTCUSDT", Type: "SELL", Quantity: decimal.NewFromInt(1)}).Error)
+
+	// A second boot must not drop anything.
+	require.NoError(t, Migrate(db))
+
+	var count int64
+	db.Model(&models.Trade{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	version, err := CurrentVersion(db)
+	assert.NoError(t, err)
+	assert.Equal(t, migrations[len(migrations)-1].Version, version)
+}
+
+func TestMigrate_AdoptsLegacySchema(t *testing.T) {
+	db := openTestDB(t)
+	// Simulate a database created by the old drop-and-recreate AutoMigrate.
+	require.NoError(t, db.AutoMigrate(&models.Coin{}, &models.Pair{}, &models.Trade{}))
+	require.NoError(t, db.Create(&models.Coin{Symbol: "BTC", Enabled: true}).Error)
+
+	require.NoError(t, Migrate(db))
+
+	var coin models.Coin
+	assert.NoError(t, db.Where("symbol = ?", "BTC").First(&coin).Error)
+}
+
+func TestMigrate_StoresAmountsAsText(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, migrate(db, migrations[:5]))
+	require.NoError(t, db.Exec("INSERT INTO coins (symbol, quantity, locked, enabled) VALUES ('BTC', 0.3, 0, true)").Error)
+	require.NoError(t, db.Exec("INSERT INTO trades (symbol, price, quantity, quote_quantity, commission) VALUES ('BTCUSDT', 60000.5, 0.1, 6000.05, 0)").Error)
+
+	require.NoError(t, Migrate(db))
+
+	var coin models.Coin
+	require.NoError(t, db.Where("symbol = ?", "BTC").First(&coin).Error)
+	assert.Equal(t, "0.3", coin.Quantity.String())
+	var trade models.Trade
+	require.NoError(t, db.First(&trade).Error)
+	assert.Equal(t, "60000.5", trade.Price.String())
+	assert.Equal(t, "6000.05", trade.QuoteQuantity.String())
+
+	// Rebuilding the tables must keep their indexes.
+	assert.True(t, db.Migrator().HasIndex(&models.Coin{}, "Symbol"))
+	assert.True(t, db.Migrator().HasIndex(&models.Pair{}, "idx_from_to"))
+	assert.True(t, db.Migrator().HasIndex(&models.Jump{}, "State"))
+
+	// An amount that has no exact float representation survives a round trip.
+	sum := decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+	require.NoError(t, db.Model(&coin).Update("quantity", sum).Error)
+	require.NoError(t, db.First(&coin, coin.ID).Error)
+	assert.True(t, sum.Equal(coin.Quantity))
+	var storage string
+	require.NoError(t, db.Raw("SELECT typeof(quantity) FROM coins WHERE id = ?", coin.ID).Scan(&storage).Error)
+	assert.Equal(t, "text", storage)
+
+	require.NoError(t, Rollback(db, 5))
+	require.NoError(t, db.Where("symbol = ?", "BTC").First(&coin).Error)
+	assert.Equal(t, "0.3", coin.Quantity.String())
+}
+
+func TestMigrate_StoresPairRatiosAsText(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, migrate(db, migrations[:9]))
+	require.NoError(t, db.Exec("INSERT INTO pairs (from_coin_symbol, to_coin_symbol, ratio, min_qty, enabled) VALUES ('BTC', 'ETH', 15.5, '0.01', true)").Error)
+
+	require.NoError(t, Migrate(db))
+
+	var pair models.Pair
+	require.NoError(t, db.First(&pair).Error)
+	assert.Equal(t, "15.5", pair.Ratio.String())
+	assert.Equal(t, "0.01", pair.MinQty.String())
+	assert.True(t, pair.Enabled)
+	assert.True(t, db.Migrator().HasIndex(&models.Pair{}, "idx_from_to"))
+
+	ratio := decimal.RequireFromString("60000").Div(decimal.RequireFromString("3910"))
+	require.NoError(t, db.Model(&pair).Update("ratio", ratio).Error)
+	require.NoError(t, db.First(&pair, pair.ID).Error)
+	assert.True(t, ratio.Equal(pair.Ratio))
+	var storage string
+	require.NoError(t, db.Raw("SELECT typeof(ratio) FROM pairs WHERE id = ?", pair.ID).Scan(&storage).Error)
+	assert.Equal(t, "text", storage)
+
+	require.NoError(t, Rollback(db, 9))
+	require.NoError(t, db.Raw("SELECT typeof(ratio) FROM pairs WHERE id = ?", pair.ID).Scan(&storage).Error)
+	assert.Equal(t, "real", storage)
+}
+
+func TestMigrate_OrderAndRollback(t *testing.T) {
+	db := openTestDB(t)
+	var applied []int
+	all := []Migration{
+		{
+			Version: 2,
+			Name:    "second",
+			Up:      func(tx *gorm.DB) error { applied = append(applied, 2); return nil },
+		},
+		{
+			Version: 1,
+			Name:    "first",
+			Up: func(tx *gorm.DB) error {
+				applied = append(applied, 1)
+				return tx.Exec("CREATE TABLE things (id integer)").Error
+			},
+			Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable("things") },
+		},
+	}
+
+	require.NoError(t, migrate(db, all))
+	assert.Equal(t, []int{1, 2}, applied)
+
+	// Migration 2 has no down step, so rolling back past it must fail.
+	err := rollback(db, all, 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be rolled back")
+
+	all[0].Down = func(tx *gorm.DB) error { return nil }
+	require.NoError(t, rollback(db, all, 0))
+	version, err := CurrentVersion(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+	assert.False(t, db.Migrator().HasTable("things"))
+}
+
+func TestSeedCoins(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, Migrate(db))
+
+	cfg := &config.Config{Trading: config.Trading{TradePairs: []string{"BTC", "ETH"}}}
+	require.NoError(t, SeedCoins(db, cfg))
+	require.NoError(t, db.Model(&models.Coin{}).Where("symbol = ?", "BTC").Update("quantity", decimal.RequireFromString("0.5")).Error)
+
+	// Re-seeding with a different configuration keeps existing rows and disables removed coins.
+	cfg.Trading.TradePairs = []string{"BTC", "BNB"}
+	require.NoError(t, SeedCoins(db, cfg))
+
+	var coins []models.Coin
+	require.NoError(t, db.Order("symbol").Find(&coins).Error)
+	require.Len(t, coins, 3)
+	assert.Equal(t, "BNB", coins[0].Symbol)
+	assert.True(t, coins[0].Enabled)
+	assert.Equal(t, "BTC", coins[1].Symbol)
+	assert.True(t, coins[1].Enabled)
+	assert.Equal(t, "0.5", coins[1].Quantity.String())
+	assert.Equal(t, "ETH", coins[2].Symbol)
+	assert.False(t, coins[2].Enabled)
+}
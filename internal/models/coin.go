@@ -1,11 +1,16 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
 
 // Coin represents a tradable coin.
+// Quantity and Locked are kept in sync with the account by the portfolio syncer.
 type Coin struct {
 	gorm.Model
-	Symbol   string  `gorm:"uniqueIndex"`
-	Quantity float64 `gorm:"not null"`
-	Enabled  bool    `gorm:"default:true"`
+	Symbol   string          `gorm:"uniqueIndex"`
+	Quantity decimal.Decimal `gorm:"type:text;not null;default:0"` // Free balance
+	Locked   decimal.Decimal `gorm:"type:text;not null;default:0"`
+	Enabled  bool            `gorm:"default:true"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// CurrentCoin is an entry in the history of coins the bot has held.
+// A new entry is written after every completed jump, and the latest one is
+// the coin a strategy resumes from after a restart.
+type CurrentCoin struct {
+	gorm.Model
+	CoinSymbol string `json:"coin" gorm:"not null"`
+}
+
+// TableName keeps the history in its own table instead of "current_coins".
+func (CurrentCoin) TableName() string {
+	return "current_coin_history"
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Jump states. A jump moves through PENDING_SELL -> SOLD -> PENDING_BUY and
+// ends in DONE, or in FAILED when the funds are back in (or never left) the
+// from coin. A direct jump trades in a single order and goes from PENDING_SELL
+// straight to DONE.
+const (
+	JumpStatePendingSell = "PENDING_SELL"
+	JumpStateSold        = "SOLD"
+	JumpStatePendingBuy  = "PENDING_BUY"
+	JumpStateDone        = "DONE"
+	JumpStateFailed      = "FAILED"
+)
+
+// Jump is the persisted progress of a jump from one coin to another, through the
+// bridge or on a direct market between the two coins.
+// It is updated before and after every exchange call, so a jump interrupted by a
+// crash or an API error can be resumed or rolled back.
+type Jump struct {
+	gorm.Model
+	FromCoinSymbol string          `json:"from_coin"`
+	ToCoinSymbol   string          `json:"to_coin"`
+	BridgeSymbol   string          `json:"bridge"`
+	State          string          `json:"state" gorm:"index"`
+	DirectSymbol   string          `json:"direct_symbol,omitempty"`          // Market of a direct jump, empty through the bridge
+	RouteID        string          `json:"route_id,omitempty" gorm:"index"`  // Multi-hop route the jump is a hop of, empty for a single jump
+	FromQuantity   decimal.Decimal `json:"from_quantity" gorm:"type:text"`   // Quantity of the from coin being traded
+	BridgeQuantity decimal.Decimal `json:"bridge_quantity" gorm:"type:text"` // Net bridge amount the sell produced
+	BuyCoinSymbol  string          `json:"buy_coin"`                         // ToCoinSymbol, or FromCoinSymbol when rolling back
+	BuyQuantity    decimal.Decimal `json:"buy_quantity" gorm:"type:text"`    // Net quantity of the buy coin received
+	BuyAttempts    int             `json:"buy_attempts"`
+	SellOrderID    int64           `json:"sell_order_id"`
+	BuyOrderID     int64           `json:"buy_order_id"`
+	Profit         float64         `json:"profit"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// IsFinished reports whether the jump has reached a final state.
+func (j *Jump) IsFinished() bool {
+	return j.State == JumpStateDone || j.State == JumpStateFailed
+}
+
+// IsRouteHop reports whether the jump is one hop of a multi-hop route.
+func (j *Jump) IsRouteHop() bool {
+	return j.RouteID != ""
+}
+
+// IsDirect reports whether the jump trades on a direct market instead of through the bridge.
+func (j *Jump) IsDirect() bool {
+	return j.DirectSymbol != ""
+}
+
+// HeldCoin returns the coin the account holds once a finished jump has settled.
+func (j *Jump) HeldCoin() string {
+	if j.State == JumpStateDone {
+		return j.BuyCoinSymbol
+	}
+	return j.FromCoinSymbol
+}
+
+// SellClientOrderID returns the client order ID of the jump's sell order, or
+// of the single order of a direct jump.
+// Client order IDs are derived from the jump, so an order whose placement had
+// an unknown outcome can be looked up instead of being placed twice. The
+// creation time keeps them unique if the database is ever reset.
+func (j *Jump) SellClientOrderID() string {
+	return fmt.Sprintf("jump-%d-%d-sell", j.ID, j.CreatedAt.Unix())
+}
+
+// BuyClientOrderID returns the client order ID of the jump's current buy
+// attempt. Every attempt, including a rollback, is a separate order.
+func (j *Jump) BuyClientOrderID() string {
+	return fmt.Sprintf("jump-%d-%d-buy-%d", j.ID, j.CreatedAt.Unix(), j.BuyAttempts)
+}
@@ -1,13 +1,20 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
 
 // Pair represents a trading pair between two coins.
-// It also stores the initial ratio used as a benchmark for trading.
+// It also stores the initial ratio used as a benchmark for trading. A pair is
+// disabled while one of its bridge symbols is not trading on the exchange, and
+// DisabledReason then says which one.
 type Pair struct {
 	gorm.Model
-	FromCoinSymbol string  `gorm:"uniqueIndex:idx_from_to"`
-	ToCoinSymbol   string  `gorm:"uniqueIndex:idx_from_to"`
-	Ratio          float64 `gorm:"not null"`
-	MinQty         float64 `gorm:"not null"`
+	FromCoinSymbol string          `gorm:"uniqueIndex:idx_from_to"`
+	ToCoinSymbol   string          `gorm:"uniqueIndex:idx_from_to"`
+	Ratio          decimal.Decimal `gorm:"type:text;not null;default:0"`
+	MinQty         decimal.Decimal `gorm:"type:text;not null;default:0"`
+	Enabled        bool            `gorm:"not null;default:true"`
+	DisabledReason string
 }
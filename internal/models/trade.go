@@ -1,16 +1,23 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
 
 // Trade represents a completed trade record in the database.
+// Amounts are exact decimals, stored as strings.
 type Trade struct {
 	gorm.Model
-	Symbol        string  `json:"symbol"`
-	Type          string  `json:"type"` // "BUY" or "SELL"
-	Price         float64 `json:"price"`
-	Quantity      float64 `json:"quantity"`
-	QuoteQuantity float64 `json:"quote_quantity"`
-	Timestamp     int64   `json:"timestamp"`
-	IsSimulation  bool    `json:"is_simulation"`
-	Profit        float64 `json:"profit,omitempty"`
+	Symbol          string          `json:"symbol"`
+	Type            string          `json:"type"` // "BUY" or "SELL"
+	OrderID         int64           `json:"order_id"`
+	Price           decimal.Decimal `json:"price" gorm:"type:text"` // Average fill price
+	Quantity        decimal.Decimal `json:"quantity" gorm:"type:text"`
+	QuoteQuantity   decimal.Decimal `json:"quote_quantity" gorm:"type:text"`
+	Commission      decimal.Decimal `json:"commission" gorm:"type:text"`
+	CommissionAsset string          `json:"commission_asset"`
+	Timestamp       int64           `json:"timestamp"`
+	IsSimulation    bool            `json:"is_simulation"`
+	Profit          float64         `json:"profit,omitempty"`
 }
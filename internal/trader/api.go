@@ -7,6 +7,9 @@ import (
 	"net/http"
 	"time"
 
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -35,6 +38,8 @@ func NewAPIServer(engine *Engine, logger *zap.Logger) *APIServer {
 func (s *APIServer) Start() {
 	http.HandleFunc("/status", s.statusHandler)
 	http.HandleFunc("/health", s.healthHandler)
+	http.HandleFunc("/portfolio", s.portfolioHandler)
+	http.HandleFunc("/pairs", s.pairsHandler)
 	// We will add /stop and /restart handlers later
 
 	s.logger.Info("Starting API server", zap.String("address", s.server.Addr))
@@ -53,17 +58,31 @@ func (s *APIServer) Stop(ctx context.Context) error {
 
 func (s *APIServer) statusHandler(w http.ResponseWriter, r *http.Request) {
 	status := struct {
-		UUID      string `json:"uuid"`
-		Name      string `json:"name"`
-		Strategy  string `json:"strategy"`
-		StartTime string `json:"start_time"`
-		Uptime    string `json:"uptime"`
+		UUID         string `json:"uuid"`
+		Name         string `json:"name"`
+		Strategy     string `json:"strategy"`
+		StartTime    string `json:"start_time"`
+		Uptime       string `json:"uptime"`
+		TimeOffsetMs int64  `json:"time_offset_ms"`           // Server clock minus local clock
+		LastTimeSync string `json:"last_time_sync,omitempty"` // When the offset was measured
+
+		RequestWeight *binance.WeightUsage `json:"request_weight,omitempty"`
 	}{
 		UUID:      s.engine.UUID,
 		Name:      s.engine.Name,
 		Strategy:  s.engine.strategy.Name(),
 		StartTime: s.engine.StartTime.Format(time.RFC3339),
-		Uptime:    time.Since(s.engine.StartTime).String(),
+		Uptime:    s.engine.clock.Now().Sub(s.engine.StartTime).String(),
+	}
+	if r, ok := s.engine.restClient.(binance.TimeOffsetReporter); ok {
+		status.TimeOffsetMs = r.TimeOffset().Milliseconds()
+		if synced := r.LastTimeSync(); !synced.IsZero() {
+			status.LastTimeSync = synced.Format(time.RFC3339)
+		}
+	}
+	if r, ok := s.engine.restClient.(binance.WeightUsageReporter); ok {
+		usage := r.WeightUsage()
+		status.RequestWeight = &usage
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -73,6 +92,63 @@ func (s *APIServer) statusHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (s *APIServer) portfolioHandler(w http.ResponseWriter, r *http.Request) {
+	portfolio := struct {
+		Balances  map[string]AssetBalance `json:"balances"`
+		UpdatedAt string                  `json:"updated_at"`
+	}{
+		Balances:  s.engine.portfolio.Balances(),
+		UpdatedAt: s.engine.portfolio.UpdatedAt().Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(portfolio); err != nil {
+		s.logger.Error("Failed to write portfolio response", zap.Error(err))
+		http.Error(w, "Failed to encode portfolio", http.StatusInternalServerError)
+	}
+}
+
+// PairStatus reports whether a pair is scouted, and why not when it is excluded.
+type PairStatus struct {
+	From    string          `json:"from"`
+	To      string          `json:"to"`
+	Ratio   decimal.Decimal `json:"ratio"`
+	Enabled bool            `json:"enabled"`
+	Reason  string          `json:"reason,omitempty"`
+}
+
+func (s *APIServer) pairsHandler(w http.ResponseWriter, r *http.Request) {
+	var pairs []models.Pair
+	if err := s.engine.db.Order("from_coin_symbol, to_coin_symbol").Find(&pairs).Error; err != nil {
+		s.logger.Error("Failed to get pairs from database", zap.Error(err))
+		http.Error(w, "Failed to get pairs", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Pairs    []PairStatus `json:"pairs"`
+		Excluded int          `json:"excluded"`
+	}{Pairs: make([]PairStatus, 0, len(pairs))}
+	for _, p := range pairs {
+		response.Pairs = append(response.Pairs, PairStatus{
+			From:    p.FromCoinSymbol,
+			To:      p.ToCoinSymbol,
+			Ratio:   p.Ratio,
+			Enabled: p.Enabled,
+			Reason:  p.DisabledReason,
+		})
+		if !p.Enabled {
+			response.Excluded++
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Error("Failed to write pairs response", zap.Error(err))
+		http.Error(w, "Failed to encode pairs", http.StatusInternalServerError)
+	}
+}
+
 func (s *APIServer) healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintln(w, "OK")
@@ -0,0 +1,71 @@
+package trader
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"binance-trade-bot-go/internal/clock"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// driftingClient is a mock client that reports a measured clock offset.
+type driftingClient struct {
+	*MockRestClient
+	offset time.Duration
+	synced time.Time
+}
+
+func (c *driftingClient) TimeOffset() time.Duration { return c.offset }
+func (c *driftingClient) LastTimeSync() time.Time   { return c.synced }
+
+func TestAPIServer_StatusReportsTimeOffset(t *testing.T) {
+	synced := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := &driftingClient{MockRestClient: new(MockRestClient), offset: -1234 * time.Millisecond, synced: synced}
+	engine := NewEngine(zap.NewNop(), &config.Config{Trading: config.Trading{Name: "test"}}, client, nil, &DefaultStrategy{})
+	clk := clock.NewManual(synced)
+	engine.SetClock(clk)
+	server := NewAPIServer(engine, zap.NewNop())
+
+	clk.Advance(90 * time.Minute)
+	rec := httptest.NewRecorder()
+	server.statusHandler(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var status map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "test", status["name"])
+	assert.Equal(t, "1h30m0s", status["uptime"])
+	assert.Equal(t, float64(-1234), status["time_offset_ms"])
+	assert.Equal(t, synced.Format(time.RFC3339), status["last_time_sync"])
+}
+
+func TestAPIServer_PairsReportsExcludedPairs(t *testing.T) {
+	db, mockClient := setupTest(t)
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "LTC", Ratio: dec("500")})
+	db.Model(&models.Pair{}).Where("to_coin_symbol = ?", "LTC").Updates(map[string]interface{}{"enabled": false, "disabled_reason": "LTCUSDT is BREAK"})
+	engine := NewEngine(zap.NewNop(), &config.Config{}, mockClient, db, &DefaultStrategy{})
+	server := NewAPIServer(engine, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	server.pairsHandler(rec, httptest.NewRequest(http.MethodGet, "/pairs", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Pairs    []PairStatus `json:"pairs"`
+		Excluded int          `json:"excluded"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.Excluded)
+	assert.Equal(t, []PairStatus{
+		{From: "BTC", To: "ETH", Ratio: dec("15"), Enabled: true},
+		{From: "BTC", To: "LTC", Ratio: dec("500"), Enabled: false, Reason: "LTCUSDT is BREAK"},
+	}, response.Pairs)
+}
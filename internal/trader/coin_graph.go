@@ -0,0 +1,175 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultMaxHops applies when the MultiHop strategy_params do not set max_hops.
+const defaultMaxHops = 3
+
+// graphEdge is a market order that turns one asset into another.
+type graphEdge struct {
+	From   string
+	To     string
+	Symbol string
+	Side   string
+	Rate   decimal.Decimal // Units of To received per unit of From, net of the fee
+	Weight float64         // -log(Rate), so the best route has the smallest total weight
+}
+
+// coinGraph models the enabled coins and the bridge as nodes and every trading
+// symbol between two of them as a pair of edges, one per order side.
+type coinGraph struct {
+	nodes map[string]bool
+	edges []graphEdge
+}
+
+// buildCoinGraph creates the graph of the enabled coins and the bridge from
+// the symbols in the exchange rules and the quotes. A sell is valued at the bid
+// and a buy at the ask, both net of the fee rate. Symbols that are not trading
+// or not quoted are left out.
+func buildCoinGraph(ctx StrategyContext, quotes Quotes) (*coinGraph, error) {
+	var coins []models.Coin
+	if err := ctx.DB.Where("enabled = ?", true).Find(&coins).Error; err != nil {
+		return nil, fmt.Errorf("could not fetch enabled coins: %w", err)
+	}
+
+	g := &coinGraph{nodes: map[string]bool{ctx.Cfg.Trading.Bridge: true}}
+	for _, c := range coins {
+		g.nodes[c.Symbol] = true
+	}
+
+	fee := afterFee(ctx)
+	for _, info := range ctx.ExchangeRules.Symbols() {
+		if !info.IsTrading() || !g.nodes[info.BaseAsset] || !g.nodes[info.QuoteAsset] {
+			continue
+		}
+		if bid, err := quotes.sellPrice(info.Symbol); err == nil {
+			g.addEdge(info.BaseAsset, info.QuoteAsset, info.Symbol, binance.OrderSideSell, bid.Mul(fee))
+		}
+		if ask, err := quotes.buyPrice(info.Symbol); err == nil {
+			g.addEdge(info.QuoteAsset, info.BaseAsset, info.Symbol, binance.OrderSideBuy, divRatio(fee, ask))
+		}
+	}
+	// A stable edge order keeps the chosen route deterministic between equal candidates.
+	sort.Slice(g.edges, func(i, j int) bool {
+		if g.edges[i].Symbol != g.edges[j].Symbol {
+			return g.edges[i].Symbol < g.edges[j].Symbol
+		}
+		return g.edges[i].Side < g.edges[j].Side
+	})
+	return g, nil
+}
+
+// addEdge adds an edge with the given rate. Only the search weight is a float,
+// the rate routes are valued at stays exact.
+func (g *coinGraph) addEdge(from, to, symbol, side string, rate decimal.Decimal) {
+	if !rate.IsPositive() {
+		return
+	}
+	g.edges = append(g.edges, graphEdge{From: from, To: to, Symbol: symbol, Side: side, Rate: rate, Weight: -math.Log(rate.InexactFloat64())})
+}
+
+// tradeRoute is a sequence of orders that trades its first coin into its last.
+type tradeRoute struct {
+	Legs []graphEdge
+	Rate decimal.Decimal // Units of the last coin received per unit of the first, net of fees
+}
+
+// From returns the coin the route starts from.
+func (r *tradeRoute) From() string {
+	return r.Legs[0].From
+}
+
+// To returns the coin the route ends in.
+func (r *tradeRoute) To() string {
+	return r.Legs[len(r.Legs)-1].To
+}
+
+// String returns the route as its coins, e.g. BTC>ETH>BNB>BTC.
+func (r *tradeRoute) String() string {
+	coins := []string{r.From()}
+	for _, leg := range r.Legs {
+		coins = append(coins, leg.To)
+	}
+	return strings.Join(coins, ">")
+}
+
+// bestRoutes returns the best route of at most maxHops legs from source to
+// every reachable coin, including source itself. It runs Bellman-Ford bounded
+// to maxHops rounds on the -log(rate) weights: a route back to source with a
+// negative total weight is a negative cycle through source, i.e. a round trip
+// that ends with more of source than it started with. Routes that visit a coin
+// twice, other than a cycle returning to source, are discarded.
+func (g *coinGraph) bestRoutes(source string, maxHops int) map[string]*tradeRoute {
+	if !g.nodes[source] {
+		return nil
+	}
+	inf := math.Inf(1)
+
+	// dist[h][coin] is the smallest weight of a route of exactly h legs, and
+	// pred[h][coin] the last leg of that route.
+	dist := []map[string]float64{{source: 0}}
+	pred := []map[string]graphEdge{{}}
+	for h := 1; h <= maxHops; h++ {
+		dist = append(dist, map[string]float64{})
+		pred = append(pred, map[string]graphEdge{})
+		for _, e := range g.edges {
+			d, ok := dist[h-1][e.From]
+			if !ok || (h > 1 && e.From == source) {
+				continue
+			}
+			if w := d + e.Weight; w < getOr(dist[h], e.To, inf) {
+				dist[h][e.To] = w
+				pred[h][e.To] = e
+			}
+		}
+	}
+
+	routes := make(map[string]*tradeRoute)
+	for h := 1; h <= maxHops; h++ {
+		for coin := range dist[h] {
+			route := reconstructRoute(pred, coin, h)
+			if route == nil {
+				continue
+			}
+			if best, ok := routes[coin]; !ok || route.Rate.GreaterThan(best.Rate) {
+				routes[coin] = route
+			}
+		}
+	}
+	return routes
+}
+
+// reconstructRoute walks the predecessor legs back from coin at round h. It
+// returns nil if the route visits a coin twice.
+func reconstructRoute(pred []map[string]graphEdge, coin string, h int) *tradeRoute {
+	legs := make([]graphEdge, h)
+	visited := map[string]bool{coin: true}
+	rate := decimal.NewFromInt(1)
+	for ; h > 0; h-- {
+		e := pred[h][coin]
+		legs[h-1] = e
+		rate = rate.Mul(e.Rate)
+		coin = e.From
+		if visited[coin] && h > 1 {
+			return nil
+		}
+		visited[coin] = true
+	}
+	return &tradeRoute{Legs: legs, Rate: rate}
+}
+
+func getOr(m map[string]float64, key string, fallback float64) float64 {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,99 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// marketRules lists trading markets as symbol, base asset, quote asset and step size.
+func marketRules(markets ...[4]string) *ExchangeRules {
+	symbols := make(map[string]binance.SymbolInfo, len(markets))
+	for _, m := range markets {
+		symbols[m[0]] = binance.SymbolInfo{Symbol: m[0], Status: binance.SymbolStatusTrading, BaseAsset: m[1], QuoteAsset: m[2],
+			Filters: []binance.Filter{{FilterType: binance.FilterTypeLotSize, StepSize: m[3], MinQty: m[3]}}}
+	}
+	return NewExchangeRules(symbols)
+}
+
+// newGraphTestContext lists BTC, ETH and BNB against USDT and each other.
+// BNB is cheap in BTC, so BTC -> BNB -> ETH -> BTC is a profitable round trip.
+func newGraphTestContext(t *testing.T, feeRate float64) (StrategyContext, *MockRestClient, Quotes) {
+	db, mockClient := setupTest(t)
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1"), Enabled: true})
+	db.Create(&models.Coin{Symbol: "ETH", Enabled: true})
+	db.Create(&models.Coin{Symbol: "BNB", Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("20.5"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "BNB", Ratio: dec("200"), Enabled: true})
+
+	ctx := StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT", FeeRate: feeRate}},
+		RestClient: mockClient,
+		DB:         db,
+		ExchangeRules: marketRules(
+			[4]string{"BTCUSDT", "BTC", "USDT", "0.00001"},
+			[4]string{"ETHUSDT", "ETH", "USDT", "0.001"},
+			[4]string{"BNBUSDT", "BNB", "USDT", "0.01"},
+			[4]string{"ETHBTC", "ETH", "BTC", "0.001"},
+			[4]string{"BNBBTC", "BNB", "BTC", "0.01"},
+			[4]string{"BNBETH", "BNB", "ETH", "0.01"},
+			// Not a coin the bot trades, so not part of the graph.
+			[4]string{"XRPBTC", "XRP", "BTC", "1"},
+		),
+	}
+	quotes := quotesFromPrices(map[string]string{
+		"BTCUSDT": "60000", "ETHUSDT": "3000", "BNBUSDT": "300",
+		"ETHBTC": "0.05", "BNBBTC": "0.0048", "BNBETH": "0.1", "XRPBTC": "0.00001",
+	})
+	return ctx, mockClient, quotes
+}
+
+func TestCoinGraph_BestRoutes(t *testing.T) {
+	ctx, _, quotes := newGraphTestContext(t, 0.001)
+	graph, err := buildCoinGraph(ctx, quotes)
+	require.NoError(t, err)
+	assert.Len(t, graph.edges, 12, "two edges per market between the coins and the bridge")
+
+	routes := graph.bestRoutes("BTC", 3)
+
+	// 1 BTC buys 208.33 BNB, which sell for 20.83 ETH, which sell for 1.0417 BTC.
+	cycle := routes["BTC"]
+	require.NotNil(t, cycle)
+	assert.Equal(t, "BTC>BNB>ETH>BTC", cycle.String())
+	assert.Equal(t, []string{"BUY", "SELL", "SELL"}, []string{cycle.Legs[0].Side, cycle.Legs[1].Side, cycle.Legs[2].Side})
+	assert.InDelta(t, 1.0417*0.999*0.999*0.999, cycle.Rate.InexactFloat64(), 1e-4)
+
+	// Going through BNB beats the 19.98 ETH the direct market gives.
+	require.NotNil(t, routes["ETH"])
+	assert.Equal(t, "BTC>BNB>ETH", routes["ETH"].String())
+	assert.NotContains(t, routes, "XRP")
+
+	// Within two hops a round trip buys back on the market it sold on, at a loss.
+	routes = graph.bestRoutes("BTC", 2)
+	if cycle, ok := routes["BTC"]; ok {
+		assert.True(t, cycle.Rate.LessThan(dec("1")), "round trip rate %s", cycle.Rate)
+	}
+	assert.Equal(t, "BTC>BNB>ETH", routes["ETH"].String())
+}
+
+func TestCoinGraph_NoCycleAtFairPrices(t *testing.T) {
+	ctx, _, quotes := newGraphTestContext(t, 0.001)
+	quotes["BNBBTC"] = Quote{Bid: dec("0.005"), Ask: dec("0.005")}
+	graph, err := buildCoinGraph(ctx, quotes)
+	require.NoError(t, err)
+
+	for coin, route := range graph.bestRoutes("BTC", 3) {
+		if coin == "BTC" {
+			assert.True(t, route.Rate.LessThan(dec("1")), "round trip %s at rate %s", route, route.Rate)
+		}
+		for i, leg := range route.Legs[1:] {
+			assert.NotEqual(t, "BTC", leg.From, "route %s leaves BTC again at leg %d", route, i+2)
+		}
+	}
+}
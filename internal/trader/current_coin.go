@@ -0,0 +1,125 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// setCurrentCoin appends a coin to the current coin history.
+func setCurrentCoin(ctx StrategyContext, symbol string) error {
+	if err := ctx.DB.Create(&models.CurrentCoin{CoinSymbol: symbol}).Error; err != nil {
+		return fmt.Errorf("could not record current coin %s: %w", symbol, err)
+	}
+	return nil
+}
+
+// lastCurrentCoin returns the most recently recorded current coin, or an empty
+// string if none was ever recorded.
+func lastCurrentCoin(ctx StrategyContext) (string, error) {
+	var current models.CurrentCoin
+	err := ctx.DB.Order("id DESC").First(&current).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not fetch current coin: %w", err)
+	}
+	return current.CoinSymbol, nil
+}
+
+// resolveStartingCoin decides which coin a strategy starts scouting from.
+// In order of preference that is the last recorded current coin, the
+// configured trading.starting_coin, and finally the enabled coin with the
+// largest balance on the account. Coins that are no longer enabled are skipped.
+// A coin chosen by either fallback is recorded, so later restarts resume from it.
+func resolveStartingCoin(ctx StrategyContext) (string, error) {
+	var coins []models.Coin
+	if err := ctx.DB.Where("enabled = ?", true).Find(&coins).Error; err != nil {
+		return "", fmt.Errorf("could not fetch enabled coins: %w", err)
+	}
+	enabled := make(map[string]bool, len(coins))
+	for _, c := range coins {
+		enabled[c.Symbol] = true
+	}
+
+	last, err := lastCurrentCoin(ctx)
+	if err != nil {
+		return "", err
+	}
+	if enabled[last] {
+		ctx.Logger.Info("Resuming from last recorded coin", zap.String("coin", last))
+		return last, nil
+	}
+	if last != "" {
+		ctx.Logger.Warn("Last recorded coin is no longer enabled, ignoring it", zap.String("coin", last))
+	}
+
+	symbol := strings.ToUpper(ctx.Cfg.Trading.StartingCoin)
+	if symbol != "" {
+		if !enabled[symbol] {
+			return "", fmt.Errorf("starting coin %s is not one of the enabled trade coins", symbol)
+		}
+		ctx.Logger.Info("Starting from configured coin", zap.String("coin", symbol))
+	} else {
+		if symbol, err = largestBalanceCoin(ctx, coins); err != nil {
+			return "", err
+		}
+		ctx.Logger.Info("Starting from coin with the largest balance", zap.String("coin", symbol))
+	}
+
+	if err := setCurrentCoin(ctx, symbol); err != nil {
+		return "", err
+	}
+	return symbol, nil
+}
+
+// largestBalanceCoin returns the coin whose total (free + locked) balance on
+// the account is worth the most in the bridge coin.
+func largestBalanceCoin(ctx StrategyContext, coins []models.Coin) (string, error) {
+	account, err := ctx.RestClient.GetAccount(ctx.Context())
+	if err != nil {
+		return "", fmt.Errorf("could not get account balances: %w", err)
+	}
+	prices, err := ctx.RestClient.GetAllTickerPrices(ctx.Context())
+	if err != nil {
+		return "", fmt.Errorf("could not get all ticker prices: %w", err)
+	}
+
+	held := make(map[string]decimal.Decimal, len(account.Balances))
+	for _, b := range account.Balances {
+		free, err1 := decimal.NewFromString(b.Free)
+		locked, err2 := decimal.NewFromString(b.Locked)
+		if err1 != nil || err2 != nil {
+			ctx.Logger.Warn("Ignoring unparsable balance", zap.String("asset", b.Asset))
+			continue
+		}
+		held[b.Asset] = free.Add(locked)
+	}
+
+	var best string
+	var bestValue decimal.Decimal
+	for _, c := range coins {
+		if !held[c.Symbol].IsPositive() {
+			continue
+		}
+		price, err := parsePrice(prices, c.Symbol+ctx.Cfg.Trading.Bridge)
+		if err != nil {
+			ctx.Logger.Warn("Could not value coin balance", zap.String("coin", c.Symbol), zap.Error(err))
+			continue
+		}
+		if value := held[c.Symbol].Mul(price); value.GreaterThan(bestValue) {
+			best, bestValue = c.Symbol, value
+		}
+	}
+
+	if best == "" {
+		return "", errors.New("no balance held in any enabled coin, set trading.starting_coin to choose one")
+	}
+	return best, nil
+}
@@ -3,11 +3,20 @@ package trader
 import (
 	"binance-trade-bot-go/internal/models"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
-	"math/rand"
-	"time"
 )
 
+func init() {
+	RegisterStrategy("Default", func(params StrategyParams) (Strategy, error) {
+		// The strategy has no parameters, any given is a mistake.
+		if err := params.Decode(&struct{}{}); err != nil {
+			return nil, err
+		}
+		return &DefaultStrategy{}, nil
+	})
+}
+
 type DefaultStrategy struct {
 	lastUsedCoinSymbol string
 }
@@ -17,18 +26,20 @@ func (s *DefaultStrategy) Name() string {
 }
 
 func (s *DefaultStrategy) Initialize(ctx StrategyContext) error {
-	var coins []models.Coin
-	if err := ctx.DB.Find(&coins).Error; err != nil {
+	var count int64
+	if err := ctx.DB.Model(&models.Coin{}).Where("enabled = ?", true).Count(&count).Error; err != nil {
 		return fmt.Errorf("could not fetch coins for initialization: %w", err)
 	}
-	if len(coins) == 0 {
+	if count == 0 {
 		ctx.Logger.Warn("No coins found in the database. DefaultStrategy will not be able to trade.")
 		return nil
 	}
 
-	// In Python version, it could be specified in config, here we just pick one
-	rand.Seed(time.Now().UnixNano())
-	s.lastUsedCoinSymbol = coins[rand.Intn(len(coins))].Symbol
+	symbol, err := resolveStartingCoin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not determine starting coin: %w", err)
+	}
+	s.lastUsedCoinSymbol = symbol
 	ctx.Logger.Info("DefaultStrategy initialized", zap.String("initial_coin", s.lastUsedCoinSymbol))
 
 	// In Python version, it would buy the initial coin if not present.
@@ -39,10 +50,10 @@ func (s *DefaultStrategy) Initialize(ctx StrategyContext) error {
 func (s *DefaultStrategy) Scout(ctx StrategyContext) error {
 	l := ctx.Logger.With(zap.String("strategy", s.Name()))
 
-	// 1. Get all ticker prices
-	prices, err := ctx.RestClient.GetAllTickerPrices()
+	// 1. Get the current quotes for the configured profit model
+	quotes, err := fetchQuotes(ctx)
 	if err != nil {
-		return fmt.Errorf("could not get all ticker prices: %w", err)
+		return err
 	}
 
 	// 2. Find the current coin to scout with
@@ -53,7 +64,7 @@ func (s *DefaultStrategy) Scout(ctx StrategyContext) error {
 	l.Info("Scouting for trades...", zap.String("from_coin", currentCoin.Symbol))
 
 	// 3. Find the best jump opportunity
-	bestOpp, err := findBestJump(ctx, &currentCoin, prices)
+	bestOpp, err := findBestJump(ctx, &currentCoin, quotes)
 	if err != nil {
 		return err
 	}
@@ -63,10 +74,15 @@ func (s *DefaultStrategy) Scout(ctx StrategyContext) error {
 		l.Info("Found best jump opportunity",
 			zap.String("from", bestOpp.Pair.FromCoinSymbol),
 			zap.String("to", bestOpp.Pair.ToCoinSymbol),
-			zap.Float64("profit_margin", bestOpp.Profit))
+			zap.Stringer("profit_margin", bestOpp.Profit))
+
+		quantity, err := jumpQuantity(ctx, &currentCoin, decimal.NewFromFloat(ctx.Cfg.Trading.Quantity))
+		if err != nil {
+			return err
+		}
 
 		// Execute the jump using the helper function
-		err := ExecuteJump(ctx, &bestOpp.Pair, ctx.Cfg.Trading.Quantity, bestOpp.Profit)
+		err = ExecuteJump(ctx, &bestOpp.Pair, quantity, bestOpp.Profit.InexactFloat64(), quotes)
 		if err != nil {
 			l.Error("Failed to execute jump", zap.Error(err))
 			// If the jump fails, we don't update the coin, we'll retry on the next tick.
@@ -81,3 +97,8 @@ func (s *DefaultStrategy) Scout(ctx StrategyContext) error {
 
 	return nil
 }
+
+// OnJumpSettled continues scouting from whichever coin a recovered jump left us holding.
+func (s *DefaultStrategy) OnJumpSettled(jump models.Jump) {
+	s.lastUsedCoinSymbol = jump.HeldCoin()
+}
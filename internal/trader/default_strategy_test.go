@@ -4,7 +4,9 @@ import (
 	"binance-trade-bot-go/internal/binance"
 	"binance-trade-bot-go/internal/config"
 	"binance-trade-bot-go/internal/models"
+	"context"
 	"errors"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -18,33 +20,70 @@ type MockRestClient struct {
 	mock.Mock
 }
 
-func (m *MockRestClient) GetServerTime() (int64, error) {
+func (m *MockRestClient) GetServerTime(ctx context.Context) (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockRestClient) GetAllTickerPrices() (map[string]string, error) {
+func (m *MockRestClient) GetAllTickerPrices(ctx context.Context) (map[string]string, error) {
 	args := m.Called()
 	return args.Get(0).(map[string]string), args.Error(1)
 }
 
-func (m *MockRestClient) GetExchangeInfo() (*binance.ExchangeInfoResponse, error) {
+func (m *MockRestClient) GetAllBookTickers(ctx context.Context) (map[string]binance.BookTicker, error) {
+	args := m.Called()
+	return args.Get(0).(map[string]binance.BookTicker), args.Error(1)
+}
+
+func (m *MockRestClient) GetExchangeInfo(ctx context.Context) (*binance.ExchangeInfoResponse, error) {
 	args := m.Called()
 	return args.Get(0).(*binance.ExchangeInfoResponse), args.Error(1)
 }
 
-func (m *MockRestClient) CreateOrder(symbol, side string, quantity float64) (*binance.CreateOrderResponse, error) {
-	args := m.Called(symbol, side, quantity)
+// CreateOrder records the quantity as the string sent to the exchange, so
+// expectations match the exact decimal.
+func (m *MockRestClient) CreateOrder(ctx context.Context, symbol, side string, quantity decimal.Decimal, clientOrderID string) (*binance.CreateOrderResponse, error) {
+	args := m.Called(symbol, side, quantity.String(), clientOrderID)
 	return args.Get(0).(*binance.CreateOrderResponse), args.Error(1)
 }
 
+func (m *MockRestClient) GetOrder(ctx context.Context, symbol string, orderID int64) (*binance.OrderResponse, error) {
+	args := m.Called(symbol, orderID)
+	return args.Get(0).(*binance.OrderResponse), args.Error(1)
+}
+
+func (m *MockRestClient) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*binance.OrderResponse, error) {
+	args := m.Called(symbol, clientOrderID)
+	return args.Get(0).(*binance.OrderResponse), args.Error(1)
+}
+
+func (m *MockRestClient) GetAccount(ctx context.Context) (*binance.AccountResponse, error) {
+	args := m.Called()
+	return args.Get(0).(*binance.AccountResponse), args.Error(1)
+}
+
+// filledOrder builds the response of a market order that was filled immediately.
+func filledOrder(orderID int64, executedQty, quoteQty string) *binance.CreateOrderResponse {
+	return &binance.CreateOrderResponse{
+		OrderID:             orderID,
+		ExecutedQuantity:    executedQty,
+		CummulativeQuoteQty: quoteQty,
+		Status:              binance.OrderStatusFilled,
+	}
+}
+
+// dec parses a decimal literal.
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
 // setupTest creates a full test environment with a mock client and in-memory DB.
 func setupTest(t *testing.T) (*gorm.DB, *MockRestClient) {
 	// Use a new, non-shared in-memory database for each test to ensure isolation.
 	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&models.Coin{}, &models.Pair{})
+	err = db.AutoMigrate(&models.Coin{}, &models.Pair{}, &models.Trade{}, &models.Jump{}, &models.CurrentCoin{})
 	assert.NoError(t, err)
 
 	mockClient := new(MockRestClient)
@@ -55,8 +94,8 @@ func setupTest(t *testing.T) (*gorm.DB, *MockRestClient) {
 func TestDefaultStrategy_Scout_NoOpportunity(t *testing.T) {
 	// Arrange
 	db, mockClient := setupTest(t)
-	db.Create(&models.Coin{Symbol: "BTC", Quantity: 1.0})
-	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 16.0, MinQty: 0.01})
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("16"), MinQty: dec("0.01")})
 
 	strategy := DefaultStrategy{lastUsedCoinSymbol: "BTC"}
 	ctx := StrategyContext{
@@ -84,11 +123,12 @@ func TestDefaultStrategy_Scout_NoOpportunity(t *testing.T) {
 func TestDefaultStrategy_Scout_PriceFetchError(t *testing.T) {
 	// Arrange
 	db, mockClient := setupTest(t)
-	db.Create(&models.Coin{Symbol: "BTC", Quantity: 1.0})
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1")})
 
 	strategy := DefaultStrategy{lastUsedCoinSymbol: "BTC"}
 	ctx := StrategyContext{
 		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{},
 		RestClient: mockClient,
 		DB:         db,
 	}
@@ -108,8 +148,8 @@ func TestDefaultStrategy_Scout_PriceFetchError(t *testing.T) {
 func TestDefaultStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 	// Arrange
 	db, mockClient := setupTest(t)
-	db.Create(&models.Coin{Symbol: "BTC", Quantity: 1.0})
-	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 15.0, MinQty: 0.01})
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
 
 	strategy := DefaultStrategy{lastUsedCoinSymbol: "BTC"}
 	ctx := StrategyContext{
@@ -124,18 +164,20 @@ func TestDefaultStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 		RestClient: mockClient,
 		DB:         db,
 		// Mock exchange rules to allow for quantity formatting
-		ExchangeRules: map[string]binance.SymbolInfo{
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
 			"BTCUSDT": {
+				Status: binance.SymbolStatusTrading,
 				Filters: []binance.Filter{
 					{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"},
 				},
 			},
 			"ETHUSDT": {
+				Status: binance.SymbolStatusTrading,
 				Filters: []binance.Filter{
 					{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"},
 				},
 			},
-		},
+		}),
 	}
 
 	// Expect a call to get prices, and return prices that ARE profitable
@@ -148,13 +190,13 @@ func TestDefaultStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 	// With a quantity of 1.0 BTC, we expect to buy 14.63 ETH (current ratio)
 	// Expect the two-step jump:
 	// 1. Sell BTC for USDT
-	mockClient.On("CreateOrder", "BTCUSDT", "SELL", 1.0).Return(&binance.CreateOrderResponse{OrderID: 1}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
 	// 2. Buy ETH with USDT
 	//    - We need to calculate the expected buy quantity:
 	//      1.0 BTC * 60000 USDT/BTC = 60000 USDT
 	//      60000 USDT / 4100 USDT/ETH = 14.634... ETH
 	//    - The formatQuantity will floor this based on the step size "0.01"
-	mockClient.On("CreateOrder", "ETHUSDT", "BUY", 15.38).Return(&binance.CreateOrderResponse{OrderID: 2}, nil)
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "15.38", mock.Anything).Return(filledOrder(2, "15.38", "59982"), nil)
 
 	// Act
 	err := strategy.Scout(ctx)
@@ -162,13 +204,17 @@ func TestDefaultStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t) // Verifies that CreateOrder was called
+	assert.Equal(t, "ETH", strategy.lastUsedCoinSymbol)
+	current, err := lastCurrentCoin(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ETH", current)
 }
 
 func TestDefaultStrategy_Scout_ProfitableTrade_OrderFails(t *testing.T) {
 	// Arrange
 	db, mockClient := setupTest(t)
-	db.Create(&models.Coin{Symbol: "BTC", Quantity: 1.0})
-	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 15.0, MinQty: 0.01})
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
 
 	strategy := DefaultStrategy{lastUsedCoinSymbol: "BTC"}
 	ctx := StrategyContext{
@@ -182,18 +228,20 @@ func TestDefaultStrategy_Scout_ProfitableTrade_OrderFails(t *testing.T) {
 		},
 		RestClient: mockClient,
 		DB:         db,
-		ExchangeRules: map[string]binance.SymbolInfo{
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
 			"BTCUSDT": {
+				Status: binance.SymbolStatusTrading,
 				Filters: []binance.Filter{
 					{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"},
 				},
 			},
 			"ETHUSDT": {
+				Status: binance.SymbolStatusTrading,
 				Filters: []binance.Filter{
 					{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"},
 				},
 			},
-		},
+		}),
 	}
 
 	mockClient.On("GetAllTickerPrices").Return(map[string]string{
@@ -203,9 +251,9 @@ func TestDefaultStrategy_Scout_ProfitableTrade_OrderFails(t *testing.T) {
 
 	// Expect a call to create an order, but it fails
 	// Expect the first step (SELL BTC) to succeed
-	mockClient.On("CreateOrder", "BTCUSDT", "SELL", 1.0).Return(&binance.CreateOrderResponse{OrderID: 1}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
 	// Expect the second step (BUY ETH) to fail
-	mockClient.On("CreateOrder", "ETHUSDT", "BUY", 15.38).Return(
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "15.38", mock.Anything).Return(
 		&binance.CreateOrderResponse{},
 		errors.New("insufficient funds"),
 	)
@@ -218,3 +266,98 @@ func TestDefaultStrategy_Scout_ProfitableTrade_OrderFails(t *testing.T) {
 	assert.Contains(t, err.Error(), "insufficient funds")
 	mockClient.AssertExpectations(t)
 }
+
+func TestDefaultStrategy_Initialize(t *testing.T) {
+	newCtx := func(t *testing.T, startingCoin string) (StrategyContext, *MockRestClient) {
+		db, mockClient := setupTest(t)
+		db.Create(&models.Coin{Symbol: "BTC", Enabled: true})
+		db.Create(&models.Coin{Symbol: "ETH", Enabled: true})
+		db.Create(&models.Coin{Symbol: "LTC", Enabled: true})
+		db.Model(&models.Coin{}).Where("symbol = ?", "LTC").Update("enabled", false)
+		return StrategyContext{
+			Logger:     zap.NewNop(),
+			Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT", StartingCoin: startingCoin}},
+			RestClient: mockClient,
+			DB:         db,
+		}, mockClient
+	}
+
+	t.Run("Resumes from the last recorded coin", func(t *testing.T) {
+		ctx, mockClient := newCtx(t, "BTC")
+		ctx.DB.Create(&models.CurrentCoin{CoinSymbol: "BTC"})
+		ctx.DB.Create(&models.CurrentCoin{CoinSymbol: "ETH"})
+
+		strategy := DefaultStrategy{}
+		assert.NoError(t, strategy.Initialize(ctx))
+
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, "ETH", strategy.lastUsedCoinSymbol)
+		var count int64
+		ctx.DB.Model(&models.CurrentCoin{}).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("Uses the configured starting coin on first run", func(t *testing.T) {
+		ctx, mockClient := newCtx(t, "eth")
+
+		strategy := DefaultStrategy{}
+		assert.NoError(t, strategy.Initialize(ctx))
+
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, "ETH", strategy.lastUsedCoinSymbol)
+		current, err := lastCurrentCoin(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "ETH", current)
+	})
+
+	t.Run("Ignores a recorded coin that is no longer enabled", func(t *testing.T) {
+		ctx, _ := newCtx(t, "BTC")
+		ctx.DB.Create(&models.CurrentCoin{CoinSymbol: "LTC"})
+
+		strategy := DefaultStrategy{}
+		assert.NoError(t, strategy.Initialize(ctx))
+		assert.Equal(t, "BTC", strategy.lastUsedCoinSymbol)
+	})
+
+	t.Run("Rejects a starting coin that is not enabled", func(t *testing.T) {
+		ctx, _ := newCtx(t, "LTC")
+
+		strategy := DefaultStrategy{}
+		err := strategy.Initialize(ctx)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "LTC")
+	})
+
+	t.Run("Falls back to the largest balance by value", func(t *testing.T) {
+		ctx, mockClient := newCtx(t, "")
+		mockClient.On("GetAccount").Return(&binance.AccountResponse{Balances: []binance.Balance{
+			{Asset: "BTC", Free: "0.01", Locked: "0"},  // 600 USDT
+			{Asset: "ETH", Free: "0.1", Locked: "0.1"}, // 800 USDT
+			{Asset: "LTC", Free: "100", Locked: "0"},   // disabled
+			{Asset: "USDT", Free: "5000", Locked: "0"}, // bridge, not a trade coin
+		}}, nil)
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "4000", "LTCUSDT": "80"}, nil)
+
+		strategy := DefaultStrategy{}
+		assert.NoError(t, strategy.Initialize(ctx))
+
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, "ETH", strategy.lastUsedCoinSymbol)
+		current, err := lastCurrentCoin(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "ETH", current)
+	})
+
+	t.Run("Fails when no enabled coin is held", func(t *testing.T) {
+		ctx, mockClient := newCtx(t, "")
+		mockClient.On("GetAccount").Return(&binance.AccountResponse{Balances: []binance.Balance{{Asset: "USDT", Free: "5000", Locked: "0"}}}, nil)
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "4000"}, nil)
+
+		strategy := DefaultStrategy{}
+		err := strategy.Initialize(ctx)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "starting_coin")
+	})
+}
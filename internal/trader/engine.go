@@ -2,9 +2,11 @@ package trader
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/clock"
 	"binance-trade-bot-go/internal/config"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -16,73 +18,165 @@ type Engine struct {
 	logger     *zap.Logger
 	cfg        *config.Config
 	db         *gorm.DB
-	restClient *binance.RestClient
+	restClient binance.RestClientInterface
 	strategy   Strategy
+	portfolio  *Portfolio
+	rules      *ExchangeRules
+	prices     PriceSource
+	clock      clock.Clock
 	UUID       string
 	Name       string
 	StartTime  time.Time
 }
 
 // NewEngine creates a new trading engine with a specific strategy.
-func NewEngine(logger *zap.Logger, cfg *config.Config, restClient *binance.RestClient, db *gorm.DB, strategy Strategy) *Engine {
+func NewEngine(logger *zap.Logger, cfg *config.Config, restClient binance.RestClientInterface, db *gorm.DB, strategy Strategy) *Engine {
 	return &Engine{
 		logger:     logger,
 		cfg:        cfg,
 		db:         db,
 		restClient: restClient,
 		strategy:   strategy,
+		portfolio:  NewPortfolio(),
+		rules:      NewExchangeRules(nil),
+		clock:      clock.Real(),
 		UUID:       uuid.New().String(),
 		Name:       cfg.Trading.Name,
 		StartTime:  time.Now(),
 	}
 }
 
+// SetPriceSource makes the engine read market prices from source instead of
+// the REST client. It must be called before Run.
+func (e *Engine) SetPriceSource(source PriceSource) {
+	e.prices = source
+}
+
+// SetClock makes the engine, and the strategy through its context, tell the
+// time and schedule its ticks with c instead of the wall clock. It must be
+// called before Run and restarts StartTime at the clock's current time.
+func (e *Engine) SetClock(c clock.Clock) {
+	e.clock = c
+	e.StartTime = c.Now()
+}
+
 // Run starts the trading engine's main loop.
 func (e *Engine) Run(ctx context.Context) {
-	e.logger.Info("Initializing trading strategy...", zap.String("strategy", e.strategy.Name()))
-
-	// Fetch and cache exchange info
-	e.logger.Info("Fetching exchange information...")
-	info, err := e.restClient.GetExchangeInfo()
+	strategyCtx, err := e.Setup(ctx)
 	if err != nil {
-		e.logger.Fatal("could not get exchange info", zap.Error(err))
+		e.logger.Fatal("Failed to start trading engine", zap.Error(err))
+	}
+
+	// Using the interval from config for the ticker.
+	interval := time.Duration(e.cfg.Trading.TickInterval) * time.Second
+	ticker := e.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	syncInterval := time.Duration(e.cfg.Trading.PortfolioSyncInterval) * time.Second
+	if syncInterval <= 0 {
+		syncInterval = defaultPortfolioSyncInterval
 	}
-	exchangeRules := make(map[string]binance.SymbolInfo)
-	for _, s := range info.Symbols {
-		exchangeRules[s.Symbol] = s
+	syncTicker := e.clock.NewTicker(syncInterval)
+	defer syncTicker.Stop()
+
+	// Symbols can change status or filters at any time, keep the rules current
+	refreshInterval := time.Duration(e.cfg.Trading.ExchangeInfoRefreshInterval) * time.Second
+	if refreshInterval <= 0 {
+		refreshInterval = defaultExchangeInfoRefreshInterval
 	}
-	e.logger.Info("Successfully cached exchange information", zap.Int("count", len(exchangeRules)))
+	go refreshExchangeRules(strategyCtx, refreshInterval)
+
+	e.logger.Info("Starting scout loop", zap.String("strategy", e.strategy.Name()), zap.Duration("interval", interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Stopping trading engine...")
+			return
+		case <-syncTicker.C():
+			syncPortfolio(strategyCtx)
+		case <-ticker.C():
+			e.Tick(strategyCtx)
+		}
+	}
+}
+
+// Setup prepares the engine for scouting: it loads the exchange rules and the
+// balances, creates the pairs, initializes the strategy and settles any jump a
+// previous run left half-finished. It returns the context the strategy runs
+// with. Run calls it before its loop; a caller driving the engine itself, like
+// a backtest, calls Tick afterwards.
+func (e *Engine) Setup(ctx context.Context) (StrategyContext, error) {
+	e.logger.Info("Initializing trading strategy...", zap.String("strategy", e.strategy.Name()))
 
 	// Create the context for the strategy
 	strategyCtx := StrategyContext{
+		Ctx:           ctx,
 		Logger:        e.logger,
 		Cfg:           e.cfg,
 		RestClient:    e.restClient,
 		DB:            e.db,
-		ExchangeRules: exchangeRules,
+		ExchangeRules: e.rules,
+		Portfolio:     e.portfolio,
+		Prices:        e.prices,
+		Clock:         e.clock,
+	}
+
+	// Fetch and cache exchange info
+	e.logger.Info("Fetching exchange information...")
+	if err := e.rules.Refresh(strategyCtx); err != nil {
+		return strategyCtx, fmt.Errorf("could not get exchange info: %w", err)
+	}
+	e.logger.Info("Successfully cached exchange information", zap.Int("count", e.rules.Len()))
+
+	// Load the account balances before anything is sized from them
+	if err := e.portfolio.Sync(strategyCtx); err != nil {
+		e.logger.Error("Failed to sync portfolio, using last known coin quantities", zap.Error(err))
+	}
+
+	// Make sure every enabled coin can be scouted against every other one
+	if err := InitializePairs(strategyCtx); err != nil {
+		return strategyCtx, fmt.Errorf("failed to initialize trading pairs: %w", err)
+	}
+	if err := updatePairStatus(strategyCtx); err != nil {
+		e.logger.Error("Failed to update pair status", zap.Error(err))
 	}
 
 	if err := e.strategy.Initialize(strategyCtx); err != nil {
-		e.logger.Fatal("Failed to initialize strategy", zap.Error(err))
+		return strategyCtx, fmt.Errorf("failed to initialize strategy: %w", err)
 	}
 	e.logger.Info("Strategy initialized successfully.")
 
-	// Using the interval from config for the ticker.
-	interval := time.Duration(e.cfg.Trading.TickInterval) * time.Second
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	// Resume or roll back any jump a previous run left half-finished
+	e.settleJumps(strategyCtx)
+	return strategyCtx, nil
+}
 
-	e.logger.Info("Starting scout loop", zap.String("strategy", e.strategy.Name()), zap.Duration("interval", interval))
+// Tick runs a single scout cycle: it settles pending jumps and lets the
+// strategy scout, unless a jump is still in flight.
+func (e *Engine) Tick(ctx StrategyContext) {
+	if !e.settleJumps(ctx) {
+		e.logger.Warn("A jump is still in flight, skipping scout")
+		return
+	}
+	if err := e.strategy.Scout(ctx); err != nil {
+		e.logger.Error("Strategy scout failed", zap.Error(err), zap.String("strategy", e.strategy.Name()))
+	}
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			e.logger.Info("Stopping trading engine...")
-			return
-		case <-ticker.C:
-			if err := e.strategy.Scout(strategyCtx); err != nil {
-				e.logger.Error("Strategy scout failed", zap.Error(err), zap.String("strategy", e.strategy.Name()))
-			}
+// settleJumps recovers incomplete jumps and tells the strategy about every one
+// that was settled. It returns false while a jump is still in flight, in which
+// case the strategy must not scout because funds are not where it expects.
+func (e *Engine) settleJumps(ctx StrategyContext) bool {
+	settled, pending, err := RecoverJumps(ctx)
+	if err != nil {
+		e.logger.Error("Jump recovery failed", zap.Error(err))
+		return false
+	}
+	if aware, ok := e.strategy.(JumpAware); ok {
+		for _, jump := range settled {
+			aware.OnJumpSettled(jump)
 		}
 	}
+	return pending == 0
 }
@@ -0,0 +1,227 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultExchangeInfoRefreshInterval applies when trading.exchange_info_refresh_interval is not set.
+const defaultExchangeInfoRefreshInterval = 15 * time.Minute
+
+// ExchangeRules holds the symbol definitions of the exchange, their status and
+// filters. It is reloaded by Refresh while strategies read it, and is safe for
+// concurrent use. A nil ExchangeRules knows no symbols.
+type ExchangeRules struct {
+	mu        sync.RWMutex
+	symbols   map[string]binance.SymbolInfo
+	updatedAt time.Time
+}
+
+// NewExchangeRules creates exchange rules from symbol definitions keyed by symbol.
+func NewExchangeRules(symbols map[string]binance.SymbolInfo) *ExchangeRules {
+	return &ExchangeRules{symbols: symbols}
+}
+
+// Symbol returns the definition of a symbol.
+func (r *ExchangeRules) Symbol(symbol string) (binance.SymbolInfo, bool) {
+	if r == nil {
+		return binance.SymbolInfo{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.symbols[symbol]
+	return info, ok
+}
+
+// IsTrading reports whether a symbol is known and currently accepts orders.
+func (r *ExchangeRules) IsTrading(symbol string) bool {
+	info, ok := r.Symbol(symbol)
+	return ok && info.IsTrading()
+}
+
+// Len returns the number of known symbols.
+func (r *ExchangeRules) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.symbols)
+}
+
+// Symbols returns the definitions of all known symbols.
+func (r *ExchangeRules) Symbols() []binance.SymbolInfo {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	symbols := make([]binance.SymbolInfo, 0, len(r.symbols))
+	for _, info := range r.symbols {
+		symbols = append(symbols, info)
+	}
+	return symbols
+}
+
+// UpdatedAt returns the time of the last successful refresh.
+func (r *ExchangeRules) UpdatedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.updatedAt
+}
+
+// Refresh reloads the symbol definitions from the exchange and logs every
+// symbol whose status or filters changed since the last refresh.
+func (r *ExchangeRules) Refresh(ctx StrategyContext) error {
+	info, err := ctx.RestClient.GetExchangeInfo(ctx.Context())
+	if err != nil {
+		return fmt.Errorf("could not get exchange info: %w", err)
+	}
+	symbols := make(map[string]binance.SymbolInfo, len(info.Symbols))
+	for _, s := range info.Symbols {
+		symbols[s.Symbol] = s
+	}
+
+	r.mu.Lock()
+	previous := r.symbols
+	r.symbols = symbols
+	r.updatedAt = ctx.clock().Now()
+	r.mu.Unlock()
+
+	if len(previous) > 0 {
+		logRuleChanges(ctx.Logger, previous, symbols)
+	}
+	return nil
+}
+
+// logRuleChanges logs the symbols that were removed, changed status or had a
+// filter changed between two sets of exchange rules.
+func logRuleChanges(logger *zap.Logger, previous, current map[string]binance.SymbolInfo) {
+	for symbol, old := range previous {
+		info, ok := current[symbol]
+		if !ok {
+			logger.Warn("Symbol removed from the exchange", zap.String("symbol", symbol))
+			continue
+		}
+		if old.Status != info.Status {
+			logger.Warn("Symbol status changed",
+				zap.String("symbol", symbol),
+				zap.String("from", old.Status),
+				zap.String("to", info.Status))
+		}
+
+		oldFilters := filtersByType(old.Filters)
+		newFilters := filtersByType(info.Filters)
+		for filterType, f := range newFilters {
+			if previousFilter, ok := oldFilters[filterType]; !ok || !reflect.DeepEqual(previousFilter, f) {
+				logger.Info("Symbol filter changed",
+					zap.String("symbol", symbol),
+					zap.String("filter", filterType),
+					zap.Any("from", oldFilters[filterType]),
+					zap.Any("to", f))
+			}
+		}
+		for filterType := range oldFilters {
+			if _, ok := newFilters[filterType]; !ok {
+				logger.Info("Symbol filter removed", zap.String("symbol", symbol), zap.String("filter", filterType))
+			}
+		}
+	}
+}
+
+func filtersByType(filters []binance.Filter) map[string]binance.Filter {
+	byType := make(map[string]binance.Filter, len(filters))
+	for _, f := range filters {
+		byType[f.FilterType] = f
+	}
+	return byType
+}
+
+// errPairNotTrading is returned when a pair cannot be traded because one of
+// its bridge symbols is not TRADING.
+var errPairNotTrading = errors.New("pair is not trading")
+
+// pairExclusion returns why a pair cannot be traded, or an empty string when
+// both of its bridge symbols are TRADING.
+func pairExclusion(ctx StrategyContext, pair *models.Pair) string {
+	bridge := ctx.Cfg.Trading.Bridge
+	for _, coin := range []string{pair.FromCoinSymbol, pair.ToCoinSymbol} {
+		if coin == bridge {
+			continue
+		}
+		symbol := coin + bridge
+		if !ctx.ExchangeRules.IsTrading(symbol) {
+			return fmt.Sprintf("%s is %s", symbol, symbolStatus(ctx, symbol))
+		}
+	}
+	return ""
+}
+
+// updatePairStatus disables every pair whose bridge symbols do not accept
+// orders, e.g. during a BREAK, so it is not scouted, and enables it again once
+// both symbols are TRADING. Nothing changes while no exchange rules are loaded.
+func updatePairStatus(ctx StrategyContext) error {
+	if ctx.ExchangeRules.Len() == 0 {
+		return nil
+	}
+
+	var pairs []models.Pair
+	if err := ctx.DB.Find(&pairs).Error; err != nil {
+		return fmt.Errorf("could not fetch pairs: %w", err)
+	}
+	for _, pair := range pairs {
+		reason := pairExclusion(ctx, &pair)
+		tradable := reason == ""
+		if tradable == pair.Enabled && reason == pair.DisabledReason {
+			continue
+		}
+		err := ctx.DB.Model(&pair).Updates(map[string]interface{}{"enabled": tradable, "disabled_reason": reason}).Error
+		if err != nil {
+			return fmt.Errorf("could not update pair %s/%s: %w", pair.FromCoinSymbol, pair.ToCoinSymbol, err)
+		}
+		l := ctx.Logger.With(zap.String("from", pair.FromCoinSymbol), zap.String("to", pair.ToCoinSymbol))
+		if tradable {
+			l.Info("Enabled pair, its symbols are trading again")
+		} else {
+			l.Warn("Disabled pair, one of its symbols is not trading", zap.String("reason", reason))
+		}
+	}
+	return nil
+}
+
+// symbolStatus returns the status of a symbol for logging.
+func symbolStatus(ctx StrategyContext, symbol string) string {
+	info, ok := ctx.ExchangeRules.Symbol(symbol)
+	if !ok {
+		return "UNKNOWN"
+	}
+	return info.Status
+}
+
+// refreshExchangeRules reloads the exchange rules every interval until ctx is
+// cancelled, and updates which pairs can be traded after each refresh.
+func refreshExchangeRules(ctx StrategyContext, interval time.Duration) {
+	ticker := ctx.clock().NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Context().Done():
+			return
+		case <-ticker.C():
+			if err := ctx.ExchangeRules.Refresh(ctx); err != nil {
+				ctx.Logger.Warn("Failed to refresh exchange rules, keeping the previous ones", zap.Error(err))
+				continue
+			}
+			if err := updatePairStatus(ctx); err != nil {
+				ctx.Logger.Error("Failed to update pair status", zap.Error(err))
+			}
+		}
+	}
+}
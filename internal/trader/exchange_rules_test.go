@@ -0,0 +1,144 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func testSymbol(symbol, status, stepSize string) binance.SymbolInfo {
+	return binance.SymbolInfo{Symbol: symbol, Status: status, Filters: []binance.Filter{
+		{FilterType: binance.FilterTypeLotSize, StepSize: stepSize, MinQty: stepSize},
+	}}
+}
+
+func TestExchangeRules_Refresh(t *testing.T) {
+	_, mockClient := setupTest(t)
+	core, logs := observer.New(zapcore.InfoLevel)
+	ctx := StrategyContext{Logger: zap.New(core), RestClient: mockClient}
+
+	mockClient.On("GetExchangeInfo").Return(&binance.ExchangeInfoResponse{Symbols: []binance.SymbolInfo{
+		testSymbol("BTCUSDT", binance.SymbolStatusTrading, "0.00001"),
+		testSymbol("ETHUSDT", binance.SymbolStatusTrading, "0.0001"),
+	}}, nil).Once()
+	mockClient.On("GetExchangeInfo").Return(&binance.ExchangeInfoResponse{Symbols: []binance.SymbolInfo{
+		testSymbol("BTCUSDT", binance.SymbolStatusBreak, "0.00001"),
+		testSymbol("ETHUSDT", binance.SymbolStatusTrading, "0.001"),
+	}}, nil).Once()
+	mockClient.On("GetExchangeInfo").Return((*binance.ExchangeInfoResponse)(nil), errors.New("timeout")).Once()
+
+	rules := NewExchangeRules(nil)
+	require.NoError(t, rules.Refresh(ctx))
+	assert.True(t, rules.IsTrading("BTCUSDT"))
+	assert.Zero(t, logs.Len(), "the first load has nothing to compare against")
+
+	require.NoError(t, rules.Refresh(ctx))
+	assert.False(t, rules.IsTrading("BTCUSDT"))
+	eth, ok := rules.Symbol("ETHUSDT")
+	require.True(t, ok)
+	assert.Equal(t, "0.001", eth.Filters[0].StepSize)
+
+	statusLogs := logs.FilterMessage("Symbol status changed").All()
+	require.Len(t, statusLogs, 1)
+	assert.Equal(t, "BTCUSDT", statusLogs[0].ContextMap()["symbol"])
+	assert.Equal(t, binance.SymbolStatusBreak, statusLogs[0].ContextMap()["to"])
+	filterLogs := logs.FilterMessage("Symbol filter changed").All()
+	require.Len(t, filterLogs, 1)
+	assert.Equal(t, "ETHUSDT", filterLogs[0].ContextMap()["symbol"])
+	assert.Equal(t, binance.FilterTypeLotSize, filterLogs[0].ContextMap()["filter"])
+
+	// A failed refresh keeps the previous rules.
+	assert.Error(t, rules.Refresh(ctx))
+	assert.Equal(t, 2, rules.Len())
+}
+
+func TestUpdatePairStatus(t *testing.T) {
+	db, mockClient := setupTest(t)
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("10")})
+	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: dec("0.1")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "LTC", Ratio: dec("500")})
+
+	rules := NewExchangeRules(map[string]binance.SymbolInfo{
+		"BTCUSDT": testSymbol("BTCUSDT", binance.SymbolStatusTrading, "0.00001"),
+		"ETHUSDT": testSymbol("ETHUSDT", binance.SymbolStatusBreak, "0.0001"),
+		"LTCUSDT": testSymbol("LTCUSDT", binance.SymbolStatusTrading, "0.001"),
+	})
+	ctx := StrategyContext{
+		Logger:        zap.NewNop(),
+		Cfg:           &config.Config{Trading: config.Trading{Bridge: "USDT"}},
+		RestClient:    mockClient,
+		DB:            db,
+		ExchangeRules: rules,
+	}
+	pair := func(from, to string) models.Pair {
+		var pair models.Pair
+		require.NoError(t, db.Where("from_coin_symbol = ? AND to_coin_symbol = ?", from, to).First(&pair).Error)
+		return pair
+	}
+
+	require.NoError(t, updatePairStatus(ctx))
+
+	assert.False(t, pair("BTC", "ETH").Enabled)
+	assert.Equal(t, "ETHUSDT is BREAK", pair("BTC", "ETH").DisabledReason)
+	assert.False(t, pair("ETH", "BTC").Enabled)
+	assert.True(t, pair("BTC", "LTC").Enabled)
+	assert.Empty(t, pair("BTC", "LTC").DisabledReason)
+
+	// A disabled pair is not scouted even when it looks profitable.
+	quotes := quotesFromPrices(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "1000", "LTCUSDT": "120"})
+	opp, err := findBestJump(ctx, &models.Coin{Symbol: "BTC"}, quotes)
+	require.NoError(t, err)
+	assert.Nil(t, opp)
+
+	// The pairs come back once ETH trades again.
+	mockClient.On("GetExchangeInfo").Return(&binance.ExchangeInfoResponse{Symbols: []binance.SymbolInfo{
+		testSymbol("BTCUSDT", binance.SymbolStatusTrading, "0.00001"),
+		testSymbol("ETHUSDT", binance.SymbolStatusTrading, "0.0001"),
+		testSymbol("LTCUSDT", binance.SymbolStatusTrading, "0.001"),
+	}}, nil)
+	require.NoError(t, rules.Refresh(ctx))
+	require.NoError(t, updatePairStatus(ctx))
+
+	assert.True(t, pair("BTC", "ETH").Enabled)
+	assert.Empty(t, pair("BTC", "ETH").DisabledReason)
+	assert.True(t, pair("ETH", "BTC").Enabled)
+}
+
+func TestFindBestJump_SkipsSymbolsNotTrading(t *testing.T) {
+	db, mockClient := setupTest(t)
+	// Both pairs are still enabled, the rules changed since the last status update.
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("10"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "LTC", Ratio: dec("400"), Enabled: true})
+
+	ctx := StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT"}},
+		RestClient: mockClient,
+		DB:         db,
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": testSymbol("BTCUSDT", binance.SymbolStatusTrading, "0.00001"),
+			"ETHUSDT": testSymbol("ETHUSDT", binance.SymbolStatusHalt, "0.0001"),
+			"LTCUSDT": testSymbol("LTCUSDT", binance.SymbolStatusTrading, "0.001"),
+		}),
+	}
+	quotes := quotesFromPrices(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "1000", "LTCUSDT": "120"})
+
+	_, err := calculateProfitForPair(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("10")}, quotes)
+	assert.ErrorIs(t, err, errPairNotTrading)
+	assert.Contains(t, err.Error(), "ETHUSDT is HALT")
+
+	// ETH would be the far better jump, but only LTC can be traded.
+	opp, err := findBestJump(ctx, &models.Coin{Symbol: "BTC"}, quotes)
+	require.NoError(t, err)
+	require.NotNil(t, opp)
+	assert.Equal(t, "LTC", opp.Pair.ToCoinSymbol)
+}
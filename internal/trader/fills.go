@@ -0,0 +1,186 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+var (
+	// defaultOrderFillTimeout applies when trading.order_fill_timeout is not set.
+	defaultOrderFillTimeout = 30 * time.Second
+	// orderPollInterval is how often an unfilled order is re-queried.
+	orderPollInterval = time.Second
+)
+
+// orderLeg describes a single market order of a jump.
+type orderLeg struct {
+	Symbol     string
+	Side       string
+	BaseAsset  string
+	QuoteAsset string
+}
+
+// orderFill is the reconciled outcome of an order once the exchange reports it
+// as done: what was actually executed, at which average price, and what it cost.
+type orderFill struct {
+	OrderID         int64
+	Symbol          string
+	Side            string
+	BaseAsset       string
+	QuoteAsset      string
+	Price           decimal.Decimal // Average fill price
+	Quantity        decimal.Decimal // Executed base asset quantity
+	QuoteQuantity   decimal.Decimal // Executed quote asset quantity
+	Commission      decimal.Decimal
+	CommissionAsset string
+	TransactTime    int64
+}
+
+// received returns the net amount of asset credited to the account by this fill.
+func (f *orderFill) received(asset string) decimal.Decimal {
+	gross := f.Quantity
+	if f.Side == binance.OrderSideSell {
+		gross = f.QuoteQuantity
+	}
+	if f.CommissionAsset == asset {
+		return gross.Sub(f.Commission)
+	}
+	return gross
+}
+
+// waitForFill waits until an order is no longer working and reconciles its fill.
+// Orders that come back FILLED from CreateOrder are reconciled straight from the
+// response; anything else is polled via GetOrder until it reaches a final status
+// or the configured timeout expires.
+func waitForFill(ctx StrategyContext, leg orderLeg, order *binance.CreateOrderResponse) (*orderFill, error) {
+	fill, err := fillFromCreateResponse(order)
+	if err != nil {
+		return nil, err
+	}
+	fill.Symbol, fill.Side = leg.Symbol, leg.Side
+	fill.BaseAsset, fill.QuoteAsset = leg.BaseAsset, leg.QuoteAsset
+	if order.Status == binance.OrderStatusFilled {
+		if len(order.Fills) == 0 {
+			fill.estimateCommission(ctx.Cfg.Trading.FeeRate)
+		}
+		return fill, nil
+	}
+
+	timeout := time.Duration(ctx.Cfg.Trading.OrderFillTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultOrderFillTimeout
+	}
+	deadline := ctx.clock().Now().Add(timeout)
+
+	for {
+		status, err := ctx.RestClient.GetOrder(ctx.Context(), leg.Symbol, order.OrderID)
+		if err != nil {
+			ctx.Logger.Warn("Failed to query order status", zap.Int64("orderId", order.OrderID), zap.Error(err))
+		} else if fill, err := reconcileOrderStatus(ctx, leg, status); err != nil || fill != nil {
+			return fill, err
+		}
+
+		if ctx.clock().Now().After(deadline) {
+			return nil, fmt.Errorf("order %d for %s not filled within %s", order.OrderID, leg.Symbol, timeout)
+		}
+		select {
+		case <-ctx.clock().After(orderPollInterval):
+		case <-ctx.Context().Done():
+			return nil, fmt.Errorf("stopped waiting for order %d for %s: %w", order.OrderID, leg.Symbol, ctx.Context().Err())
+		}
+	}
+}
+
+// reconcileOrderStatus interprets a GET /order response. It returns the fill
+// once the order is final, nil while it is still working, and an error if it
+// ended without executing anything. That endpoint does not report commissions,
+// so they are estimated from the fee rate.
+func reconcileOrderStatus(ctx StrategyContext, leg orderLeg, status *binance.OrderResponse) (*orderFill, error) {
+	switch status.Status {
+	case binance.OrderStatusFilled:
+	case binance.OrderStatusCanceled, binance.OrderStatusRejected,
+		binance.OrderStatusExpired, binance.OrderStatusExpiredInMatch:
+		// A market order can expire after a partial fill when liquidity runs out.
+		// Whatever did execute is real and must be accounted for.
+		if executed, _ := parseAmount(status.ExecutedQuantity); !executed.IsPositive() {
+			return nil, fmt.Errorf("order %d for %s ended with status %s", status.OrderID, leg.Symbol, status.Status)
+		}
+		ctx.Logger.Warn("Order ended partially filled", zap.Int64("orderId", status.OrderID), zap.String("status", status.Status))
+	default:
+		return nil, nil
+	}
+
+	fill := &orderFill{
+		OrderID:      status.OrderID,
+		Symbol:       leg.Symbol,
+		Side:         leg.Side,
+		BaseAsset:    leg.BaseAsset,
+		QuoteAsset:   leg.QuoteAsset,
+		TransactTime: status.UpdateTime,
+	}
+	if err := fill.setExecuted(status.ExecutedQuantity, status.CummulativeQuoteQty); err != nil {
+		return nil, fmt.Errorf("invalid fill for order %d: %w", status.OrderID, err)
+	}
+	fill.estimateCommission(ctx.Cfg.Trading.FeeRate)
+	return fill, nil
+}
+
+// fillFromCreateResponse builds a fill from the order placement response,
+// summing the commissions of the individual fills.
+func fillFromCreateResponse(order *binance.CreateOrderResponse) (*orderFill, error) {
+	fill := &orderFill{
+		OrderID:      order.OrderID,
+		TransactTime: order.TransactTime,
+	}
+	if err := fill.setExecuted(order.ExecutedQuantity, order.CummulativeQuoteQty); err != nil {
+		return nil, fmt.Errorf("invalid fill for order %d: %w", order.OrderID, err)
+	}
+
+	for _, f := range order.Fills {
+		commission, err := decimal.NewFromString(f.Commission)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commission %q for order %d: %w", f.Commission, order.OrderID, err)
+		}
+		fill.Commission = fill.Commission.Add(commission)
+		fill.CommissionAsset = f.CommissionAsset
+	}
+	return fill, nil
+}
+
+// estimateCommission charges the fee rate in the received asset, which is what
+// Binance does when fees are not paid in BNB.
+func (f *orderFill) estimateCommission(feeRate float64) {
+	rate := decimal.NewFromFloat(feeRate)
+	if f.Side == binance.OrderSideSell {
+		f.Commission, f.CommissionAsset = f.QuoteQuantity.Mul(rate), f.QuoteAsset
+	} else {
+		f.Commission, f.CommissionAsset = f.Quantity.Mul(rate), f.BaseAsset
+	}
+}
+
+// setExecuted parses the executed quantities and derives the average price.
+func (f *orderFill) setExecuted(executedQty, quoteQty string) error {
+	var err error
+	if f.Quantity, err = parseAmount(executedQty); err != nil {
+		return err
+	}
+	if f.QuoteQuantity, err = parseAmount(quoteQty); err != nil {
+		return err
+	}
+	if f.Quantity.IsPositive() {
+		f.Price = f.QuoteQuantity.Div(f.Quantity)
+	}
+	return nil
+}
+
+// parseAmount parses a decimal string from the API, treating an empty string as zero.
+func parseAmount(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(value)
+}
@@ -0,0 +1,188 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/clock"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWaitForFill(t *testing.T) {
+	orderPollInterval = time.Millisecond
+	defer func() { orderPollInterval = time.Second }()
+
+	sellLeg := orderLeg{Symbol: "BTCUSDT", Side: binance.OrderSideSell, BaseAsset: "BTC", QuoteAsset: "USDT"}
+	newCtx := func(client *MockRestClient) StrategyContext {
+		return StrategyContext{
+			Logger:     zap.NewNop(),
+			Cfg:        &config.Config{Trading: config.Trading{FeeRate: 0.001, OrderFillTimeout: 1}},
+			RestClient: client,
+		}
+	}
+
+	t.Run("Filled response uses reported commissions", func(t *testing.T) {
+		order := filledOrder(1, "0.5", "30000")
+		order.Fills = []binance.Fill{
+			{Price: "59990", Quantity: "0.2", Commission: "12", CommissionAsset: "USDT"},
+			{Price: "60006.67", Quantity: "0.3", Commission: "18", CommissionAsset: "USDT"},
+		}
+
+		fill, err := waitForFill(newCtx(new(MockRestClient)), sellLeg, order)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "60000", fill.Price.String())
+		assert.Equal(t, "30", fill.Commission.String())
+		assert.Equal(t, "29970", fill.received("USDT").String())
+	})
+
+	t.Run("Working order is polled until filled", func(t *testing.T) {
+		client := new(MockRestClient)
+		client.On("GetOrder", "BTCUSDT", int64(7)).Return(&binance.OrderResponse{OrderID: 7, Status: binance.OrderStatusNew}, nil).Once()
+		client.On("GetOrder", "BTCUSDT", int64(7)).Return((*binance.OrderResponse)(nil), errors.New("timeout")).Once()
+		client.On("GetOrder", "BTCUSDT", int64(7)).Return(&binance.OrderResponse{
+			OrderID:             7,
+			Status:              binance.OrderStatusFilled,
+			ExecutedQuantity:    "0.5",
+			CummulativeQuoteQty: "29500",
+			UpdateTime:          1234,
+		}, nil).Once()
+
+		fill, err := waitForFill(newCtx(client), sellLeg, &binance.CreateOrderResponse{OrderID: 7, Status: binance.OrderStatusNew})
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+		assert.Equal(t, "59000", fill.Price.String())
+		assert.Equal(t, int64(1234), fill.TransactTime)
+		// GET /order has no commissions, so the fee rate is charged in the received asset.
+		assert.Equal(t, "USDT", fill.CommissionAsset)
+		assert.Equal(t, "29.5", fill.Commission.String())
+	})
+
+	t.Run("Expired order without executions fails", func(t *testing.T) {
+		client := new(MockRestClient)
+		client.On("GetOrder", "BTCUSDT", int64(8)).Return(&binance.OrderResponse{OrderID: 8, Status: binance.OrderStatusExpired, ExecutedQuantity: "0"}, nil)
+
+		_, err := waitForFill(newCtx(client), sellLeg, &binance.CreateOrderResponse{OrderID: 8, Status: binance.OrderStatusNew})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "EXPIRED")
+	})
+
+	t.Run("Order that never fills times out", func(t *testing.T) {
+		client := new(MockRestClient)
+		client.On("GetOrder", "BTCUSDT", int64(9)).Return(&binance.OrderResponse{OrderID: 9, Status: binance.OrderStatusNew}, nil)
+		ctx := newCtx(client)
+		ctx.Cfg.Trading.OrderFillTimeout = 30
+		clk := clock.NewManual(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		ctx.Clock = clk
+
+		done := make(chan error)
+		go func() {
+			_, err := waitForFill(ctx, sellLeg, &binance.CreateOrderResponse{OrderID: 9, Status: binance.OrderStatusNew})
+			done <- err
+		}()
+		// Move the clock 10s forward every time the poll loop waits.
+		var err error
+		for waiting := true; waiting; {
+			select {
+			case err = <-done:
+				waiting = false
+			default:
+				if clk.Waiters() > 0 {
+					clk.Advance(10 * time.Second)
+				}
+				runtime.Gosched()
+			}
+		}
+
+		assert.ErrorContains(t, err, "not filled within 30s")
+		// Polled at 0s, 10s, 20s, 30s and 40s, past the deadline.
+		client.AssertNumberOfCalls(t, "GetOrder", 5)
+	})
+
+	t.Run("Engine shutdown stops polling", func(t *testing.T) {
+		client := new(MockRestClient)
+		client.On("GetOrder", "BTCUSDT", int64(10)).Return(&binance.OrderResponse{OrderID: 10, Status: binance.OrderStatusNew}, nil)
+		ctx := newCtx(client)
+		runCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		ctx.Ctx = runCtx
+
+		_, err := waitForFill(ctx, sellLeg, &binance.CreateOrderResponse{OrderID: 10, Status: binance.OrderStatusNew})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		client.AssertNumberOfCalls(t, "GetOrder", 1)
+	})
+}
+
+func TestExecuteJump_BuySizedFromSellProceeds(t *testing.T) {
+	// Arrange
+	db, mockClient := setupTest(t)
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15")})
+
+	ctx := StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT", FeeRate: 0.001}},
+		RestClient: mockClient,
+		DB:         db,
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
+			"ETHUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"}}},
+		}),
+	}
+
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+	// The sell slips to 59000 and pays 59 USDT commission: 58941 USDT / 3900 = 15.113 ETH.
+	sell := filledOrder(1, "1", "59000")
+	sell.Fills = []binance.Fill{{Price: "59000", Quantity: "1", Commission: "59", CommissionAsset: "USDT"}}
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(sell, nil)
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "15.11", mock.Anything).Return(filledOrder(2, "15.11", "58929"), nil)
+
+	// Act
+	err := ExecuteJump(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}, dec("1"), 0.01, testQuotes)
+
+	// Assert
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	var trades []models.Trade
+	db.Order("id").Find(&trades)
+	if assert.Len(t, trades, 2) {
+		assert.Equal(t, int64(1), trades[0].OrderID)
+		assert.Equal(t, "59000", trades[0].Price.String())
+		assert.Equal(t, "59", trades[0].Commission.String())
+		assert.Equal(t, "USDT", trades[0].CommissionAsset)
+		assert.Equal(t, int64(2), trades[1].OrderID)
+		assert.Equal(t, "3900", trades[1].Price.String())
+		assert.Equal(t, "0.01511", trades[1].Commission.String())
+		assert.Equal(t, "ETH", trades[1].CommissionAsset)
+		assert.Equal(t, 0.01, trades[1].Profit)
+	}
+}
+
+func TestExecuteJump_BidAskBuySizedAtAsk(t *testing.T) {
+	ctx, mockClient := newJumpTestContext(t)
+	ctx.Cfg.Trading.ProfitModel = ProfitModelBidAsk
+
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+	mockClient.On("GetAllBookTickers").Return(map[string]binance.BookTicker{
+		"BTCUSDT": {Symbol: "BTCUSDT", BidPrice: "59990", AskPrice: "60010"},
+		"ETHUSDT": {Symbol: "ETHUSDT", BidPrice: "3999", AskPrice: "4000"},
+	}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
+	// 60000 USDT buys 15 ETH at the ask, not 15.38 at the last price.
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "15", mock.Anything).Return(filledOrder(2, "15", "60000"), nil)
+
+	err := ExecuteJump(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}, dec("1"), 0.01, testQuotes)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
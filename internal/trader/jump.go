@@ -0,0 +1,312 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const defaultJumpMaxBuyAttempts = 3
+
+// saveJump persists the current state of a jump.
+func saveJump(ctx StrategyContext, jump *models.Jump) error {
+	if err := ctx.DB.Save(jump).Error; err != nil {
+		return fmt.Errorf("could not persist jump %d in state %s: %w", jump.ID, jump.State, err)
+	}
+	return nil
+}
+
+// failJump records an error on the jump and moves it to the given state.
+// It returns the original error so callers can return it directly.
+func failJump(ctx StrategyContext, jump *models.Jump, state string, cause error) error {
+	jump.State = state
+	jump.Error = cause.Error()
+	if err := saveJump(ctx, jump); err != nil {
+		ctx.Logger.Error("Failed to persist jump failure", zap.Uint("jump_id", jump.ID), zap.Error(err))
+	}
+	return cause
+}
+
+// executeSellLeg sells the from coin for the bridge and moves the jump to SOLD.
+// If the order may have been placed but its fill cannot be confirmed, the jump
+// stays in PENDING_SELL, so recovery can look the order up later.
+func executeSellLeg(ctx StrategyContext, jump *models.Jump) error {
+	leg := sellLeg(jump)
+
+	order, err := ctx.RestClient.CreateOrder(ctx.Context(), leg.Symbol, leg.Side, jump.FromQuantity, jump.SellClientOrderID())
+	if err != nil {
+		state := models.JumpStateFailed
+		if errors.Is(err, binance.ErrOrderStatusUnknown) {
+			// The order may have been placed, recovery looks it up by its client order ID.
+			state = models.JumpStatePendingSell
+		}
+		return failJump(ctx, jump, state, fmt.Errorf("failed to execute sell order for %s: %w", leg.Symbol, err))
+	}
+	ctx.Logger.Info("Sell order created", zap.Uint("jump_id", jump.ID), zap.Int64("orderId", order.OrderID))
+
+	jump.SellOrderID = order.OrderID
+	if err := saveJump(ctx, jump); err != nil {
+		ctx.Logger.Error("Failed to persist sell order", zap.Error(err))
+	}
+
+	fill, err := waitForFill(ctx, leg, order)
+	if err != nil {
+		return failJump(ctx, jump, models.JumpStatePendingSell, fmt.Errorf("failed to confirm sell order for %s: %w", leg.Symbol, err))
+	}
+	return completeSellLeg(ctx, jump, fill)
+}
+
+// sellLeg returns the order selling the from coin of a jump for the bridge.
+func sellLeg(jump *models.Jump) orderLeg {
+	return orderLeg{
+		Symbol:     jump.FromCoinSymbol + jump.BridgeSymbol,
+		Side:       binance.OrderSideSell,
+		BaseAsset:  jump.FromCoinSymbol,
+		QuoteAsset: jump.BridgeSymbol,
+	}
+}
+
+// directLeg returns the single order of a direct jump. On a market quoted in
+// the from coin the to coin is bought, otherwise the from coin is sold.
+func directLeg(jump *models.Jump) orderLeg {
+	if jump.DirectSymbol == jump.ToCoinSymbol+jump.FromCoinSymbol {
+		return orderLeg{
+			Symbol:     jump.DirectSymbol,
+			Side:       binance.OrderSideBuy,
+			BaseAsset:  jump.ToCoinSymbol,
+			QuoteAsset: jump.FromCoinSymbol,
+		}
+	}
+	return orderLeg{
+		Symbol:     jump.DirectSymbol,
+		Side:       binance.OrderSideSell,
+		BaseAsset:  jump.FromCoinSymbol,
+		QuoteAsset: jump.ToCoinSymbol,
+	}
+}
+
+// executeDirectLeg places the single order of a direct jump and finishes it.
+// Like a sell, the jump stays in PENDING_SELL if the order may have been placed
+// but its fill cannot be confirmed.
+func executeDirectLeg(ctx StrategyContext, jump *models.Jump, quantity decimal.Decimal) error {
+	leg := directLeg(jump)
+
+	order, err := ctx.RestClient.CreateOrder(ctx.Context(), leg.Symbol, leg.Side, quantity, jump.SellClientOrderID())
+	if err != nil {
+		state := models.JumpStateFailed
+		if errors.Is(err, binance.ErrOrderStatusUnknown) {
+			// The order may have been placed, recovery looks it up by its client order ID.
+			state = models.JumpStatePendingSell
+		}
+		return failJump(ctx, jump, state, fmt.Errorf("failed to execute direct order for %s: %w", leg.Symbol, err))
+	}
+	ctx.Logger.Info("Direct order created", zap.Uint("jump_id", jump.ID), zap.Int64("orderId", order.OrderID))
+
+	jump.SellOrderID = order.OrderID
+	if err := saveJump(ctx, jump); err != nil {
+		ctx.Logger.Error("Failed to persist direct order", zap.Error(err))
+	}
+
+	fill, err := waitForFill(ctx, leg, order)
+	if err != nil {
+		return failJump(ctx, jump, models.JumpStatePendingSell, fmt.Errorf("failed to confirm direct order for %s: %w", leg.Symbol, err))
+	}
+	return completeDirectLeg(ctx, jump, fill)
+}
+
+// completeDirectLeg records the filled order of a direct jump and finishes it.
+// The ratios are re-baselined at the bridge price of the to coin, since the
+// fill price is quoted in the from coin.
+func completeDirectLeg(ctx StrategyContext, jump *models.Jump, fill *orderFill) error {
+	recordTrade(ctx, fill, jump.Profit)
+
+	jump.State = models.JumpStateDone
+	jump.BuyCoinSymbol = jump.ToCoinSymbol
+	jump.BuyQuantity = fill.received(jump.ToCoinSymbol)
+	jump.Error = ""
+	if err := saveJump(ctx, jump); err != nil {
+		return err
+	}
+
+	if jump.IsRouteHop() {
+		settleRouteHop(ctx, jump)
+	} else {
+		prices, err := ctx.priceSource().GetAllTickerPrices(ctx.Context())
+		if err != nil {
+			ctx.Logger.Warn("Could not get prices to update ratios after direct jump", zap.Error(err))
+		}
+		if coinPrice, err := parsePrice(prices, jump.ToCoinSymbol+jump.BridgeSymbol); err != nil {
+			ctx.Logger.Error("Failed to update pair ratios, the new coin has no bridge price", zap.Error(err))
+			if err := setCurrentCoin(ctx, jump.ToCoinSymbol); err != nil {
+				ctx.Logger.Error("Failed to record current coin", zap.Error(err))
+			}
+		} else {
+			rebaseline(ctx, jump.ToCoinSymbol, coinPrice, prices)
+		}
+	}
+	syncPortfolio(ctx)
+
+	ctx.Logger.Info("Jump transaction finished.",
+		zap.Uint("jump_id", jump.ID),
+		zap.String("state", jump.State),
+		zap.String("new_coin", jump.BuyCoinSymbol),
+		zap.String("symbol", fill.Symbol),
+		zap.Stringer("price", fill.Price),
+		zap.Stringer("received", jump.BuyQuantity))
+	return nil
+}
+
+// completeSellLeg records a filled sell and moves the jump to SOLD.
+func completeSellLeg(ctx StrategyContext, jump *models.Jump, fill *orderFill) error {
+	recordTrade(ctx, fill, 0)
+
+	jump.State = models.JumpStateSold
+	jump.BridgeQuantity = fill.received(jump.BridgeSymbol)
+	jump.Error = ""
+	ctx.Logger.Info("Sell order filled",
+		zap.Uint("jump_id", jump.ID),
+		zap.Stringer("price", fill.Price),
+		zap.Stringer("executed_qty", fill.Quantity),
+		zap.Stringer("bridge_obtained", jump.BridgeQuantity))
+	return saveJump(ctx, jump)
+}
+
+// executeBuyLeg spends the bridge amount of a SOLD jump on coin, which is the
+// jump's to coin, or its from coin when rolling back. On success the jump is
+// DONE (or FAILED after a rollback). If the order cannot be sized or placed
+// the jump returns to SOLD so the buy can be retried.
+func executeBuyLeg(ctx StrategyContext, jump *models.Jump, coin string) error {
+	leg := orderLeg{
+		Symbol:     coin + jump.BridgeSymbol,
+		Side:       binance.OrderSideBuy,
+		BaseAsset:  coin,
+		QuoteAsset: jump.BridgeSymbol,
+	}
+	jump.BuyCoinSymbol = coin
+	jump.BuyOrderID = 0
+
+	prices, err := ctx.priceSource().GetAllTickerPrices(ctx.Context())
+	if err != nil {
+		return failJump(ctx, jump, models.JumpStateSold, fmt.Errorf("could not get prices for buy leg: %w", err))
+	}
+	price, err := buyLegPrice(ctx, prices, leg.Symbol)
+	if err != nil {
+		return failJump(ctx, jump, models.JumpStateSold, fmt.Errorf("could not get price for %s: %w", leg.Symbol, err))
+	}
+	// A buy the filters reject would be rejected the same way on every retry,
+	// so it counts as an attempt. Only a missing price is exempt, it is temporary.
+	quantity, err := formatQuantity(ctx, leg.Symbol, jump.BridgeQuantity.Div(price))
+	if err != nil {
+		jump.BuyAttempts++
+		return failJump(ctx, jump, models.JumpStateSold, err)
+	}
+	if err := validateOrder(ctx, leg.Symbol, quantity, price); err != nil {
+		jump.BuyAttempts++
+		return failJump(ctx, jump, models.JumpStateSold, err)
+	}
+
+	// Persist the intent before touching the exchange. Only an order that is
+	// actually sent counts as an attempt.
+	jump.BuyAttempts++
+	jump.State = models.JumpStatePendingBuy
+	if err := saveJump(ctx, jump); err != nil {
+		return err
+	}
+
+	order, err := ctx.RestClient.CreateOrder(ctx.Context(), leg.Symbol, leg.Side, quantity, jump.BuyClientOrderID())
+	if err != nil {
+		state := models.JumpStateSold
+		if errors.Is(err, binance.ErrOrderStatusUnknown) {
+			// The order may have been placed, recovery looks it up by its client order ID.
+			state = models.JumpStatePendingBuy
+		}
+		return failJump(ctx, jump, state, fmt.Errorf("failed to execute buy order for %s: %w", leg.Symbol, err))
+	}
+	ctx.Logger.Info("Buy order created", zap.Uint("jump_id", jump.ID), zap.Int64("orderId", order.OrderID))
+
+	jump.BuyOrderID = order.OrderID
+	if err := saveJump(ctx, jump); err != nil {
+		ctx.Logger.Error("Failed to persist buy order", zap.Error(err))
+	}
+
+	fill, err := waitForFill(ctx, leg, order)
+	if err != nil {
+		return failJump(ctx, jump, models.JumpStatePendingBuy, fmt.Errorf("failed to confirm buy order for %s: %w", leg.Symbol, err))
+	}
+	return completeBuyLeg(ctx, jump, fill, prices)
+}
+
+// buyLegPrice returns the price a buy leg is sized at: the best ask with the
+// bid_ask profit model, so spending the whole bridge amount is not rejected
+// when the ask is above the last price, and the last price otherwise.
+func buyLegPrice(ctx StrategyContext, prices map[string]string, symbol string) (decimal.Decimal, error) {
+	if ctx.Cfg.Trading.ProfitModel != ProfitModelBidAsk {
+		return parsePrice(prices, symbol)
+	}
+	quotes, err := fetchQuotes(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return quotes.buyPrice(symbol)
+}
+
+// completeBuyLeg records a filled buy and finishes the jump.
+func completeBuyLeg(ctx StrategyContext, jump *models.Jump, fill *orderFill, prices map[string]string) error {
+	rolledBack := jump.BuyCoinSymbol != jump.ToCoinSymbol
+
+	jump.BuyQuantity = fill.received(jump.BuyCoinSymbol)
+	if rolledBack {
+		recordTrade(ctx, fill, 0)
+		jump.State = models.JumpStateFailed
+		jump.Error = "rolled back to " + jump.FromCoinSymbol
+	} else {
+		// Store the overall profit in the final leg of the jump
+		recordTrade(ctx, fill, jump.Profit)
+		jump.State = models.JumpStateDone
+		jump.Error = ""
+	}
+	if err := saveJump(ctx, jump); err != nil {
+		return err
+	}
+
+	if jump.IsRouteHop() {
+		settleRouteHop(ctx, jump)
+	} else if !rolledBack {
+		rebaseline(ctx, jump.BuyCoinSymbol, fill.Price, prices)
+	}
+
+	// The balances changed on both legs, refresh them before the next jump is sized.
+	syncPortfolio(ctx)
+
+	ctx.Logger.Info("Jump transaction finished.",
+		zap.Uint("jump_id", jump.ID),
+		zap.String("state", jump.State),
+		zap.String("new_coin", jump.BuyCoinSymbol),
+		zap.Stringer("price", fill.Price),
+		zap.Stringer("received", jump.BuyQuantity))
+	return nil
+}
+
+// rebaseline records coin as the current coin after a jump into it, and
+// re-baselines the ratios around it so the jump is not immediately reversed.
+func rebaseline(ctx StrategyContext, coin string, coinPrice decimal.Decimal, prices map[string]string) {
+	if err := setCurrentCoin(ctx, coin); err != nil {
+		ctx.Logger.Error("Failed to record current coin", zap.Error(err))
+	}
+	if err := updateRatiosAfterJump(ctx, coin, coinPrice, prices); err != nil {
+		ctx.Logger.Error("Failed to update pair ratios", zap.Error(err))
+	}
+}
+
+// settleRouteHop records the coin a finished hop of a route left the account
+// holding, so a restart resumes from where the funds are. The ratios are only
+// re-baselined once the whole route has finished.
+func settleRouteHop(ctx StrategyContext, jump *models.Jump) {
+	if err := setCurrentCoin(ctx, jump.HeldCoin()); err != nil {
+		ctx.Logger.Error("Failed to record current coin", zap.Error(err))
+	}
+}
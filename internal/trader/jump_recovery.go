@@ -0,0 +1,166 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// RecoverJumps settles jumps left unfinished by a crash or a failed leg.
+// Orders that were placed are reconciled from the exchange. A SOLD jump buys
+// its to coin again until trading.jump_max_buy_attempts is reached, and is then
+// rolled back by buying the from coin with the bridge, so funds do not stay
+// stranded in the bridge coin. A rollback that fails as many times is given up
+// and the jump is marked FAILED for manual action.
+// It returns the jumps that reached a final state and the number of jumps that
+// are still in flight.
+func RecoverJumps(ctx StrategyContext) ([]models.Jump, int, error) {
+	var jumps []models.Jump
+	err := ctx.DB.Where("state IN ?", []string{
+		models.JumpStatePendingSell,
+		models.JumpStateSold,
+		models.JumpStatePendingBuy,
+	}).Order("id").Find(&jumps).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not fetch incomplete jumps: %w", err)
+	}
+
+	var settled []models.Jump
+	pending := 0
+	for i := range jumps {
+		jump := &jumps[i]
+		l := ctx.Logger.With(
+			zap.Uint("jump_id", jump.ID),
+			zap.String("from_coin", jump.FromCoinSymbol),
+			zap.String("to_coin", jump.ToCoinSymbol),
+			zap.String("state", jump.State),
+		)
+		l.Warn("Recovering incomplete jump")
+
+		if err := recoverJump(ctx, jump); err != nil {
+			l.Error("Jump recovery attempt failed", zap.Error(err))
+		}
+		if jump.IsFinished() {
+			l.Info("Incomplete jump settled", zap.String("final_state", jump.State), zap.String("held_coin", jump.HeldCoin()))
+			if jump.IsRouteHop() {
+				finishRecoveredRoute(ctx, jump)
+			}
+			settled = append(settled, *jump)
+		} else {
+			pending++
+		}
+	}
+
+	return settled, pending, nil
+}
+
+// recoverJump advances a single incomplete jump as far as possible.
+func recoverJump(ctx StrategyContext, jump *models.Jump) error {
+	switch jump.State {
+	case models.JumpStatePendingSell:
+		leg := sellLeg(jump)
+		if jump.IsDirect() {
+			leg = directLeg(jump)
+		}
+		fill, err := queryFill(ctx, leg, jump.SellOrderID, jump.SellClientOrderID())
+		if err != nil {
+			if errors.Is(err, binance.ErrOrderNotFound) {
+				// The process stopped or the request failed before the sell reached the exchange.
+				return failJump(ctx, jump, models.JumpStateFailed, errors.New("sell order was never placed on the exchange"))
+			}
+			if errors.Is(err, errOrderNotExecuted) {
+				return failJump(ctx, jump, models.JumpStateFailed, err)
+			}
+			return err
+		}
+		if fill == nil {
+			return nil // still working, check again later
+		}
+		if jump.IsDirect() {
+			return completeDirectLeg(ctx, jump, fill)
+		}
+		if err := completeSellLeg(ctx, jump, fill); err != nil {
+			return err
+		}
+
+	case models.JumpStatePendingBuy:
+		leg := orderLeg{
+			Symbol:     jump.BuyCoinSymbol + jump.BridgeSymbol,
+			Side:       binance.OrderSideBuy,
+			BaseAsset:  jump.BuyCoinSymbol,
+			QuoteAsset: jump.BridgeSymbol,
+		}
+		fill, err := queryFill(ctx, leg, jump.BuyOrderID, jump.BuyClientOrderID())
+		if err != nil && !errors.Is(err, errOrderNotExecuted) && !errors.Is(err, binance.ErrOrderNotFound) {
+			return err
+		}
+		if err == nil && fill == nil {
+			return nil // still working, check again later
+		}
+		if fill != nil {
+			prices, err := ctx.priceSource().GetAllTickerPrices(ctx.Context())
+			if err != nil {
+				ctx.Logger.Warn("Could not get prices to update ratios after recovery", zap.Error(err))
+			}
+			return completeBuyLeg(ctx, jump, fill, prices)
+		}
+		// The buy was never placed or did not execute, so the bridge is still held.
+		jump.State = models.JumpStateSold
+		if err := saveJump(ctx, jump); err != nil {
+			return err
+		}
+	}
+
+	if jump.State != models.JumpStateSold {
+		return nil
+	}
+
+	maxAttempts := ctx.Cfg.Trading.JumpMaxBuyAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultJumpMaxBuyAttempts
+	}
+	coin := jump.ToCoinSymbol
+	if jump.BuyAttempts >= 2*maxAttempts {
+		// The rollback failed as many times as the buy, it will not go through
+		// on its own. Settle the jump so it stops blocking the strategy.
+		return failJump(ctx, jump, models.JumpStateFailed, fmt.Errorf(
+			"rollback to %s failed after %d attempts, %s %s is still held and needs manual action: %s",
+			jump.FromCoinSymbol, jump.BuyAttempts-maxAttempts, jump.BridgeQuantity, jump.BridgeSymbol, jump.Error))
+	}
+	if jump.BuyAttempts >= maxAttempts {
+		ctx.Logger.Warn("Buy attempts exhausted, rolling back jump",
+			zap.Uint("jump_id", jump.ID),
+			zap.Int("attempts", jump.BuyAttempts),
+			zap.String("coin", jump.FromCoinSymbol))
+		coin = jump.FromCoinSymbol
+	}
+	return executeBuyLeg(ctx, jump, coin)
+}
+
+// errOrderNotExecuted is returned when an order reached a final state without any execution.
+var errOrderNotExecuted = errors.New("order ended without being executed")
+
+// queryFill looks up an order on the exchange, by its order ID when the
+// exchange acknowledged it and by its client order ID otherwise. It returns the
+// fill once the order is final, nil while it is still working, and
+// errOrderNotExecuted if it ended without executing anything.
+func queryFill(ctx StrategyContext, leg orderLeg, orderID int64, clientOrderID string) (*orderFill, error) {
+	var status *binance.OrderResponse
+	var err error
+	if orderID != 0 {
+		status, err = ctx.RestClient.GetOrder(ctx.Context(), leg.Symbol, orderID)
+	} else {
+		status, err = ctx.RestClient.GetOrderByClientID(ctx.Context(), leg.Symbol, clientOrderID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not query order %s for %s: %w", clientOrderID, leg.Symbol, err)
+	}
+	fill, err := reconcileOrderStatus(ctx, leg, status)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errOrderNotExecuted, err)
+	}
+	return fill, nil
+}
@@ -0,0 +1,292 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testQuotes are the quotes the jumps in these tests were scouted at.
+var testQuotes = quotesFromPrices(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"})
+
+func newJumpTestContext(t *testing.T) (StrategyContext, *MockRestClient) {
+	db, mockClient := setupTest(t)
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15")})
+	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: dec("0.066")})
+
+	return StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT", JumpMaxBuyAttempts: 2}},
+		RestClient: mockClient,
+		DB:         db,
+	}, mockClient
+}
+
+func TestExecuteJump_FailedBuyLeavesJumpSold(t *testing.T) {
+	ctx, mockClient := newJumpTestContext(t)
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
+	// 60000 / 3900, there is no LOT_SIZE filter to round it
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "15.3846153846153846", mock.Anything).
+		Return((*binance.CreateOrderResponse)(nil), errors.New("connection reset"))
+
+	err := ExecuteJump(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}, dec("1"), 0.01, testQuotes)
+
+	assert.Error(t, err)
+	var jump models.Jump
+	require.NoError(t, ctx.DB.First(&jump).Error)
+	assert.Equal(t, models.JumpStateSold, jump.State)
+	assert.Equal(t, int64(1), jump.SellOrderID)
+	assert.Equal(t, "60000", jump.BridgeQuantity.String())
+	assert.Equal(t, 1, jump.BuyAttempts)
+	assert.Contains(t, jump.Error, "connection reset")
+}
+
+func TestExecuteJump_MissingBuyPriceDoesNotCountAsAttempt(t *testing.T) {
+	ctx, mockClient := newJumpTestContext(t)
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000"}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
+
+	err := ExecuteJump(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}, dec("1"), 0.01, testQuotes)
+
+	assert.Error(t, err)
+	var jump models.Jump
+	require.NoError(t, ctx.DB.First(&jump).Error)
+	assert.Equal(t, models.JumpStateSold, jump.State)
+	assert.Equal(t, 0, jump.BuyAttempts)
+	mockClient.AssertNumberOfCalls(t, "CreateOrder", 1)
+}
+
+func TestExecuteJump_UnknownSellOutcomeStaysPending(t *testing.T) {
+	ctx, mockClient := newJumpTestContext(t)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).
+		Return((*binance.CreateOrderResponse)(nil), fmt.Errorf("failed to create order: %w: timeout", binance.ErrOrderStatusUnknown))
+
+	err := ExecuteJump(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}, dec("1"), 0.01, testQuotes)
+
+	assert.Error(t, err)
+	var jump models.Jump
+	require.NoError(t, ctx.DB.First(&jump).Error)
+	assert.Equal(t, models.JumpStatePendingSell, jump.State)
+	// The order was placed with the ID recovery looks it up by.
+	mockClient.AssertCalled(t, "CreateOrder", "BTCUSDT", "SELL", "1", jump.SellClientOrderID())
+}
+
+func TestRecoverJumps(t *testing.T) {
+	t.Run("Sold jump resumes the buy", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		ctx.DB.Create(&models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStateSold, BridgeQuantity: dec("39000"), BuyAttempts: 1, Profit: 0.02})
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+		mockClient.On("CreateOrder", "ETHUSDT", "BUY", "10", mock.Anything).Return(filledOrder(5, "10", "39000"), nil)
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateDone, settled[0].State)
+		assert.Equal(t, "ETH", settled[0].HeldCoin())
+		assert.Equal(t, int64(5), settled[0].BuyOrderID)
+
+		var trade models.Trade
+		require.NoError(t, ctx.DB.First(&trade).Error)
+		assert.Equal(t, 0.02, trade.Profit)
+	})
+
+	t.Run("Exhausted buy attempts roll back to the from coin", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		ctx.DB.Create(&models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStateSold, BridgeQuantity: dec("30000"), BuyAttempts: 2})
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+		mockClient.On("CreateOrder", "BTCUSDT", "BUY", "0.5", mock.Anything).Return(filledOrder(6, "0.5", "30000"), nil)
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateFailed, settled[0].State)
+		assert.Equal(t, "BTC", settled[0].HeldCoin())
+		assert.Contains(t, settled[0].Error, "rolled back")
+
+		// A rollback must not re-baseline the ratios.
+		var pair models.Pair
+		ctx.DB.Where("from_coin_symbol = ? AND to_coin_symbol = ?", "BTC", "ETH").First(&pair)
+		assert.Equal(t, "15", pair.Ratio.String())
+	})
+
+	t.Run("Buy the filters always reject is rolled back and then given up", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		// Neither 10 ETH nor 0.65 BTC reach the minimum quantity.
+		ctx.ExchangeRules = marketRules(
+			[4]string{"BTCUSDT", "BTC", "USDT", "1"},
+			[4]string{"ETHUSDT", "ETH", "USDT", "100"},
+		)
+		ctx.DB.Create(&models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStateSold, BridgeQuantity: dec("39000")})
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+
+		// Two buys and two rollbacks, one per tick.
+		for i := 1; i <= 4; i++ {
+			settled, pending, err := RecoverJumps(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, settled)
+			assert.Equal(t, 1, pending)
+
+			var jump models.Jump
+			require.NoError(t, ctx.DB.First(&jump).Error)
+			assert.Equal(t, i, jump.BuyAttempts)
+			assert.Contains(t, jump.Error, "LOT_SIZE")
+		}
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateFailed, settled[0].State)
+		assert.Equal(t, "BTC", settled[0].HeldCoin())
+		assert.Contains(t, settled[0].Error, "needs manual action")
+	})
+
+	t.Run("Rollback that keeps failing is given up", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		ctx.DB.Create(&models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStateSold, BridgeQuantity: dec("30000"), BuyAttempts: 2})
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+		mockClient.On("CreateOrder", "BTCUSDT", "BUY", "0.5", mock.Anything).
+			Return((*binance.CreateOrderResponse)(nil), errors.New("insufficient balance")).Twice()
+
+		for i := 0; i < 2; i++ {
+			settled, pending, err := RecoverJumps(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, settled)
+			assert.Equal(t, 1, pending)
+		}
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateFailed, settled[0].State)
+		assert.Equal(t, 4, settled[0].BuyAttempts)
+		assert.Contains(t, settled[0].Error, "rollback to BTC failed after 2 attempts")
+		assert.Contains(t, settled[0].Error, "insufficient balance")
+	})
+
+	t.Run("Pending sell with a filled order continues with the buy", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		ctx.DB.Create(&models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStatePendingSell, FromQuantity: dec("0.5"), SellOrderID: 3})
+		mockClient.On("GetOrder", "BTCUSDT", int64(3)).Return(&binance.OrderResponse{
+			OrderID: 3, Status: binance.OrderStatusFilled, ExecutedQuantity: "0.5", CummulativeQuoteQty: "31200",
+		}, nil)
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "62400", "ETHUSDT": "3120"}, nil)
+		mockClient.On("CreateOrder", "ETHUSDT", "BUY", "10", mock.Anything).Return(filledOrder(4, "10", "31200"), nil)
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateDone, settled[0].State)
+	})
+
+	t.Run("Unacknowledged sell that never reached the exchange fails", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		jump := models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStatePendingSell, FromQuantity: dec("0.5")}
+		ctx.DB.Create(&jump)
+		mockClient.On("GetOrderByClientID", "BTCUSDT", jump.SellClientOrderID()).
+			Return((*binance.OrderResponse)(nil), fmt.Errorf("failed to get order: %w", binance.ErrOrderNotFound))
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateFailed, settled[0].State)
+		assert.Equal(t, "BTC", settled[0].HeldCoin())
+	})
+
+	t.Run("Unacknowledged sell is found by its client order ID", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		jump := models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStatePendingSell, FromQuantity: dec("0.5")}
+		ctx.DB.Create(&jump)
+		mockClient.On("GetOrderByClientID", "BTCUSDT", jump.SellClientOrderID()).Return(&binance.OrderResponse{
+			OrderID: 3, Status: binance.OrderStatusFilled, ExecutedQuantity: "0.5", CummulativeQuoteQty: "31200",
+		}, nil)
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "62400", "ETHUSDT": "3120"}, nil)
+		firstBuy := jump
+		firstBuy.BuyAttempts = 1
+		mockClient.On("CreateOrder", "ETHUSDT", "BUY", "10", firstBuy.BuyClientOrderID()).Return(filledOrder(4, "10", "31200"), nil)
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateDone, settled[0].State)
+	})
+
+	t.Run("Unacknowledged buy is found by its client order ID", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		jump := models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStatePendingBuy, BuyCoinSymbol: "ETH", BridgeQuantity: dec("39000"), BuyAttempts: 1}
+		ctx.DB.Create(&jump)
+		mockClient.On("GetOrderByClientID", "ETHUSDT", jump.BuyClientOrderID()).Return(&binance.OrderResponse{
+			OrderID: 9, Status: binance.OrderStatusFilled, ExecutedQuantity: "10", CummulativeQuoteQty: "39000",
+		}, nil)
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		mockClient.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.Equal(t, 0, pending)
+		require.Len(t, settled, 1)
+		assert.Equal(t, models.JumpStateDone, settled[0].State)
+		assert.Equal(t, 1, settled[0].BuyAttempts)
+	})
+
+	t.Run("Working buy order stays pending", func(t *testing.T) {
+		ctx, mockClient := newJumpTestContext(t)
+		ctx.DB.Create(&models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BridgeSymbol: "USDT",
+			State: models.JumpStatePendingBuy, BuyCoinSymbol: "ETH", BuyOrderID: 9, BridgeQuantity: dec("39000"), BuyAttempts: 1})
+		mockClient.On("GetOrder", "ETHUSDT", int64(9)).Return(&binance.OrderResponse{OrderID: 9, Status: binance.OrderStatusNew}, nil)
+
+		settled, pending, err := RecoverJumps(ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, settled)
+		assert.Equal(t, 1, pending)
+	})
+}
+
+func TestDefaultStrategy_OnJumpSettled(t *testing.T) {
+	strategy := DefaultStrategy{lastUsedCoinSymbol: "BTC"}
+
+	strategy.OnJumpSettled(models.Jump{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", BuyCoinSymbol: "ETH", State: models.JumpStateDone})
+	assert.Equal(t, "ETH", strategy.lastUsedCoinSymbol)
+
+	strategy.OnJumpSettled(models.Jump{FromCoinSymbol: "ETH", ToCoinSymbol: "LTC", BuyCoinSymbol: "ETH", State: models.JumpStateFailed})
+	assert.Equal(t, "ETH", strategy.lastUsedCoinSymbol)
+}
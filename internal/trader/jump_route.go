@@ -0,0 +1,123 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// directBuyHeadroom is the share of the from coin a direct buy keeps back, so
+// an ask that moves up before the market order fills does not make it spend
+// more than the balance.
+const directBuyHeadroom = 0.001
+
+// jumpRoute is how a jump trades the from coin into the to coin: a sell and a
+// buy through the bridge, or a single order on a direct market such as ETHBTC.
+type jumpRoute struct {
+	DirectSymbol string          // Direct market of the jump, empty for the bridge route
+	Side         string          // Side of the direct order
+	Quantity     decimal.Decimal // Formatted quantity of the first order
+	Expected     decimal.Decimal // Estimated net quantity of the to coin received
+}
+
+func (r *jumpRoute) isDirect() bool {
+	return r.DirectSymbol != ""
+}
+
+// planJump values the jump through the bridge and, when the exchange lists a
+// trading market between the two coins, as a single direct order, both net of
+// fees at the scouted quotes. It returns the route that yields more of the to
+// coin. A route is only considered if its orders pass the exchange filters.
+func planJump(ctx StrategyContext, pair *models.Pair, fromCoinQuantity decimal.Decimal, quotes Quotes) (*jumpRoute, error) {
+	bridgeRoute, bridgeErr := planBridgeRoute(ctx, pair, fromCoinQuantity, quotes)
+	directRoute, directErr := planDirectRoute(ctx, pair, fromCoinQuantity, quotes)
+
+	l := ctx.Logger.With(zap.String("from_coin", pair.FromCoinSymbol), zap.String("to_coin", pair.ToCoinSymbol))
+	if directErr != nil {
+		l.Warn("Direct route cannot be used", zap.Error(directErr))
+	}
+	if directRoute == nil || directErr != nil {
+		return bridgeRoute, bridgeErr
+	}
+	if bridgeErr != nil {
+		l.Info("Bridge route cannot be used, jumping directly",
+			zap.String("symbol", directRoute.DirectSymbol),
+			zap.NamedError("bridge_error", bridgeErr))
+		return directRoute, nil
+	}
+
+	l.Info("Compared jump routes",
+		zap.String("direct_symbol", directRoute.DirectSymbol),
+		zap.Stringer("direct_expected", directRoute.Expected),
+		zap.Stringer("bridge_expected", bridgeRoute.Expected))
+	// On a tie the direct route wins, it places one order less.
+	if directRoute.Expected.GreaterThanOrEqual(bridgeRoute.Expected) {
+		return directRoute, nil
+	}
+	return bridgeRoute, nil
+}
+
+// planBridgeRoute sizes the sell of the from coin for the bridge and checks both legs.
+func planBridgeRoute(ctx StrategyContext, pair *models.Pair, fromCoinQuantity decimal.Decimal, quotes Quotes) (*jumpRoute, error) {
+	sellQuantity, err := formatQuantity(ctx, pair.FromCoinSymbol+ctx.Cfg.Trading.Bridge, fromCoinQuantity)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := validateJump(ctx, pair, sellQuantity, quotes)
+	if err != nil {
+		return nil, err
+	}
+	return &jumpRoute{Quantity: sellQuantity, Expected: expected}, nil
+}
+
+// planDirectRoute sizes a single order between the two coins. On a market
+// quoted in the from coin the to coin is bought, leaving headroom for price
+// movement, on one quoted in the to coin the from coin is sold. It returns nil
+// if there is no such market, or if it is not trading or not quoted.
+func planDirectRoute(ctx StrategyContext, pair *models.Pair, fromCoinQuantity decimal.Decimal, quotes Quotes) (*jumpRoute, error) {
+	symbol, side, ok := directSymbol(ctx, pair.FromCoinSymbol, pair.ToCoinSymbol)
+	if !ok {
+		return nil, nil
+	}
+
+	var price, quantity, expected decimal.Decimal
+	var err error
+	if side == binance.OrderSideBuy {
+		if price, err = quotes.buyPrice(symbol); err != nil {
+			return nil, nil
+		}
+		// The fee of a buy is taken from the coin received, not from the budget.
+		budget := fromCoinQuantity.Mul(decimal.NewFromFloat(1 - directBuyHeadroom))
+		if quantity, err = formatQuantity(ctx, symbol, budget.Div(price)); err != nil {
+			return nil, err
+		}
+		expected = quantity.Mul(afterFee(ctx))
+	} else {
+		if price, err = quotes.sellPrice(symbol); err != nil {
+			return nil, nil
+		}
+		if quantity, err = formatQuantity(ctx, symbol, fromCoinQuantity); err != nil {
+			return nil, err
+		}
+		expected = quantity.Mul(price).Mul(afterFee(ctx))
+	}
+	if err := validateOrder(ctx, symbol, quantity, price); err != nil {
+		return nil, fmt.Errorf("direct order: %w", err)
+	}
+	return &jumpRoute{DirectSymbol: symbol, Side: side, Quantity: quantity, Expected: expected}, nil
+}
+
+// directSymbol finds a trading market between two coins and the side of the
+// order that turns from into to.
+func directSymbol(ctx StrategyContext, from, to string) (string, string, bool) {
+	if info, ok := ctx.ExchangeRules.Symbol(to + from); ok && info.BaseAsset == to && info.QuoteAsset == from && info.IsTrading() {
+		return info.Symbol, binance.OrderSideBuy, true
+	}
+	if info, ok := ctx.ExchangeRules.Symbol(from + to); ok && info.BaseAsset == from && info.QuoteAsset == to && info.IsTrading() {
+		return info.Symbol, binance.OrderSideSell, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,127 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// directRules lists BTC and ETH against the bridge and each other on ETHBTC.
+func directRules(directStatus string) *ExchangeRules {
+	lotSize := func(step string) []binance.Filter {
+		return []binance.Filter{{FilterType: binance.FilterTypeLotSize, StepSize: step, MinQty: step}}
+	}
+	return NewExchangeRules(map[string]binance.SymbolInfo{
+		"BTCUSDT": {Symbol: "BTCUSDT", Status: binance.SymbolStatusTrading, BaseAsset: "BTC", QuoteAsset: "USDT", Filters: lotSize("0.00001")},
+		"ETHUSDT": {Symbol: "ETHUSDT", Status: binance.SymbolStatusTrading, BaseAsset: "ETH", QuoteAsset: "USDT", Filters: lotSize("0.01")},
+		"ETHBTC":  {Symbol: "ETHBTC", Status: directStatus, BaseAsset: "ETH", QuoteAsset: "BTC", Filters: lotSize("0.01")},
+	})
+}
+
+func TestPlanJump(t *testing.T) {
+	// Through the bridge 1 BTC sells for 59940 USDT after fees, which buys
+	// 15.36 ETH, or 15.3446 ETH after fees.
+	testCases := []struct {
+		name     string
+		from, to string
+		quantity string
+		status   string
+		ethBTC   string
+		symbol   string
+		side     string
+		order    string
+		expected string
+	}{
+		// The direct buy spends up to 0.999 BTC, keeping back directBuyHeadroom,
+		// and pays its fee in the ETH it receives.
+		{name: "Cheaper direct market buys the to coin", from: "BTC", to: "ETH", quantity: "1", status: binance.SymbolStatusTrading,
+			ethBTC: "0.0645", symbol: "ETHBTC", side: "BUY", order: "15.48", expected: "15.46452"},
+		// The single fee makes up for the headroom, the tie goes to the direct market.
+		{name: "Direct market at the bridge price buys the to coin", from: "BTC", to: "ETH", quantity: "1", status: binance.SymbolStatusTrading,
+			ethBTC: "0.065", symbol: "ETHBTC", side: "BUY", order: "15.36", expected: "15.34464"},
+		{name: "Dearer direct market goes through the bridge", from: "BTC", to: "ETH", quantity: "1", status: binance.SymbolStatusTrading,
+			ethBTC: "0.0655", order: "1", expected: "15.34464"},
+		{name: "Direct market not trading goes through the bridge", from: "BTC", to: "ETH", quantity: "1", status: binance.SymbolStatusBreak,
+			ethBTC: "0.065", order: "1", expected: "15.34464"},
+		// 15.36 ETH sells for 0.99931 BTC after fees, through the bridge it nets 0.9964 BTC.
+		{name: "Direct market sells the from coin", from: "ETH", to: "BTC", quantity: "15.36", status: binance.SymbolStatusTrading,
+			ethBTC: "0.065125", symbol: "ETHBTC", side: "SELL", order: "15.36", expected: "0.99931"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := newJumpTestContext(t)
+			ctx.Cfg.Trading.FeeRate = 0.001
+			ctx.ExchangeRules = directRules(tc.status)
+			quotes := quotesFromPrices(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900", "ETHBTC": tc.ethBTC})
+
+			route, err := planJump(ctx, &models.Pair{FromCoinSymbol: tc.from, ToCoinSymbol: tc.to}, dec(tc.quantity), quotes)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.symbol, route.DirectSymbol)
+			assert.Equal(t, tc.side, route.Side)
+			assert.Equal(t, tc.order, route.Quantity.String())
+			assert.Equal(t, tc.expected, route.Expected.Truncate(5).String())
+		})
+	}
+}
+
+func TestExecuteJump_DirectMarket(t *testing.T) {
+	ctx, mockClient := newJumpTestContext(t)
+	ctx.Cfg.Trading.FeeRate = 0.001
+	ctx.ExchangeRules = directRules(binance.SymbolStatusTrading)
+	quotes := quotesFromPrices(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900", "ETHBTC": "0.0645"})
+	mockClient.On("CreateOrder", "ETHBTC", "BUY", "15.48", mock.Anything).Return(filledOrder(7, "15.48", "0.99846"), nil)
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "4000", "ETHBTC": "0.0645"}, nil)
+
+	err := ExecuteJump(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}, dec("1"), 0.01, quotes)
+
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "CreateOrder", 1)
+
+	var jump models.Jump
+	require.NoError(t, ctx.DB.First(&jump).Error)
+	assert.Equal(t, models.JumpStateDone, jump.State)
+	assert.Equal(t, "ETHBTC", jump.DirectSymbol)
+	assert.Equal(t, "1", jump.FromQuantity.String())
+	assert.Equal(t, "ETH", jump.BuyCoinSymbol)
+	assert.Equal(t, int64(7), jump.SellOrderID)
+
+	var trades []models.Trade
+	require.NoError(t, ctx.DB.Find(&trades).Error)
+	require.Len(t, trades, 1)
+	assert.Equal(t, "ETHBTC", trades[0].Symbol)
+	assert.Equal(t, 0.01, trades[0].Profit)
+
+	// The ratios are re-baselined at the bridge price of ETH.
+	var pair models.Pair
+	require.NoError(t, ctx.DB.Where("from_coin_symbol = ?", "BTC").First(&pair).Error)
+	assert.Equal(t, "15", pair.Ratio.String())
+}
+
+func TestRecoverJumps_DirectJump(t *testing.T) {
+	ctx, mockClient := newJumpTestContext(t)
+	jump := models.Jump{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", BridgeSymbol: "USDT", DirectSymbol: "ETHBTC",
+		State: models.JumpStatePendingSell, FromQuantity: dec("15")}
+	ctx.DB.Create(&jump)
+	mockClient.On("GetOrderByClientID", "ETHBTC", jump.SellClientOrderID()).Return(&binance.OrderResponse{
+		OrderID: 3, Status: binance.OrderStatusFilled, ExecutedQuantity: "15", CummulativeQuoteQty: "0.975",
+	}, nil)
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+
+	settled, pending, err := RecoverJumps(ctx)
+
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, 0, pending)
+	require.Len(t, settled, 1)
+	assert.Equal(t, models.JumpStateDone, settled[0].State)
+	assert.Equal(t, "BTC", settled[0].HeldCoin())
+	assert.Equal(t, "0.975", settled[0].BuyQuantity.String())
+}
@@ -0,0 +1,138 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/models"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+func init() {
+	RegisterStrategy("MultiHop", func(params StrategyParams) (Strategy, error) {
+		p := MultiHopParams{MaxHops: defaultMaxHops}
+		if err := params.Decode(&p); err != nil {
+			return nil, err
+		}
+		if p.MaxHops < 1 {
+			return nil, fmt.Errorf("max_hops must be at least 1, got %d", p.MaxHops)
+		}
+		return &MultiHopStrategy{maxHops: p.MaxHops}, nil
+	})
+}
+
+// MultiHopParams are the strategy_params of the MultiHop strategy.
+type MultiHopParams struct {
+	// MaxHops is the most orders a route may take.
+	MaxHops int `mapstructure:"max_hops"`
+}
+
+// MultiHopStrategy scouts routes of up to max_hops orders from the current
+// coin through the graph of enabled coins and the bridge, instead of only the
+// two legs through the bridge. It takes the most profitable of the round trips
+// back to the current coin and the routes into another coin, and runs its legs
+// in sequence.
+type MultiHopStrategy struct {
+	lastUsedCoinSymbol string
+	maxHops            int
+}
+
+func (s *MultiHopStrategy) Name() string {
+	return "MultiHop"
+}
+
+func (s *MultiHopStrategy) Initialize(ctx StrategyContext) error {
+	symbol, err := resolveStartingCoin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not determine starting coin: %w", err)
+	}
+	s.lastUsedCoinSymbol = symbol
+	ctx.Logger.Info("MultiHopStrategy initialized", zap.String("initial_coin", s.lastUsedCoinSymbol))
+	return nil
+}
+
+func (s *MultiHopStrategy) Scout(ctx StrategyContext) error {
+	l := ctx.Logger.With(zap.String("strategy", s.Name()))
+
+	quotes, err := fetchQuotes(ctx)
+	if err != nil {
+		return err
+	}
+
+	var currentCoin models.Coin
+	if err := ctx.DB.Where("symbol = ?", s.lastUsedCoinSymbol).First(&currentCoin).Error; err != nil {
+		return fmt.Errorf("could not find last used coin %s in db: %w", s.lastUsedCoinSymbol, err)
+	}
+	l.Info("Scouting for routes...", zap.String("from_coin", currentCoin.Symbol))
+
+	route, profit, err := findBestRoute(ctx, currentCoin.Symbol, quotes, s.maxHops)
+	if err != nil {
+		return err
+	}
+	if route == nil {
+		l.Info("No profitable routes found in this cycle.")
+		return nil
+	}
+	l.Info("Found best route",
+		zap.String("route", route.String()),
+		zap.Int("legs", len(route.Legs)),
+		zap.Stringer("profit_margin", profit))
+
+	quantity, err := jumpQuantity(ctx, &currentCoin, decimal.NewFromFloat(ctx.Cfg.Trading.Quantity))
+	if err != nil {
+		return err
+	}
+	held, err := executeRoute(ctx, route, quantity, profit.InexactFloat64(), quotes)
+	// Scout from wherever the route left the funds, even if it failed part way.
+	// While a hop is in flight there is no such coin yet, the engine reports it
+	// through OnJumpSettled once recovery has settled the hop.
+	if held != "" {
+		s.lastUsedCoinSymbol = held
+	}
+	if err != nil {
+		l.Error("Failed to execute route", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// OnJumpSettled continues scouting from whichever coin a recovered jump left us holding.
+func (s *MultiHopStrategy) OnJumpSettled(jump models.Jump) {
+	s.lastUsedCoinSymbol = jump.HeldCoin()
+}
+
+// findBestRoute searches the coin graph for the most profitable route of at
+// most maxHops orders from coin, valued by routeProfit. It returns nil if no
+// route is profitable.
+func findBestRoute(ctx StrategyContext, coin string, quotes Quotes, maxHops int) (*tradeRoute, decimal.Decimal, error) {
+	var pairs []models.Pair
+	if err := ctx.DB.Where("from_coin_symbol = ? AND enabled = ?", coin, true).Find(&pairs).Error; err != nil {
+		return nil, decimal.Zero, fmt.Errorf("could not get pairs for coin %s: %w", coin, err)
+	}
+	pairsByTarget := make(map[string]models.Pair, len(pairs))
+	for _, p := range pairs {
+		pairsByTarget[p.ToCoinSymbol] = p
+	}
+
+	graph, err := buildCoinGraph(ctx, quotes)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+	if maxHops <= 0 {
+		maxHops = defaultMaxHops
+	}
+
+	var best *tradeRoute
+	bestProfit := decimal.Zero
+	for _, route := range graph.bestRoutes(coin, maxHops) {
+		profit, ok := routeProfit(ctx, route, pairsByTarget)
+		if !ok {
+			continue
+		}
+		ctx.Logger.Debug("Evaluated route", zap.String("route", route.String()), zap.Stringer("profit", profit))
+		if profit.GreaterThan(bestProfit) || (profit.Equal(bestProfit) && best != nil && route.String() < best.String()) {
+			best, bestProfit = route, profit
+		}
+	}
+	return best, bestProfit, nil
+}
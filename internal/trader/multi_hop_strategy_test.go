@@ -0,0 +1,170 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindBestRoute(t *testing.T) {
+	ctx, _, quotes := newGraphTestContext(t, 0)
+
+	// The round trip gains 4.2%, the route to ETH only 1.6% over the 20.5 ratio.
+	route, profit, err := findBestRoute(ctx, "BTC", quotes, 3)
+	require.NoError(t, err)
+	require.NotNil(t, route)
+	assert.Equal(t, "BTC>BNB>ETH>BTC", route.String())
+	assert.InDelta(t, 0.0417, profit.InexactFloat64(), 1e-4)
+
+	// A route into a coin without an enabled pair is not considered.
+	ctx.DB.Model(&models.Pair{}).Where("to_coin_symbol = ?", "ETH").Update("ratio", 10)
+	ctx.DB.Model(&models.Pair{}).Where("to_coin_symbol = ?", "ETH").Update("enabled", false)
+	route, _, err = findBestRoute(ctx, "BTC", quotes, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "BTC>BNB>ETH>BTC", route.String())
+
+	// Nothing is profitable once BNB is fairly priced.
+	quotes["BNBBTC"] = Quote{Bid: dec("0.005"), Ask: dec("0.005")}
+	route, _, err = findBestRoute(ctx, "BTC", quotes, 3)
+	require.NoError(t, err)
+	assert.Nil(t, route)
+}
+
+func TestMultiHopStrategy_Scout_ExecutesLegsInSequence(t *testing.T) {
+	ctx, mockClient, _ := newGraphTestContext(t, 0)
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{
+		"BTCUSDT": "60000", "ETHUSDT": "3000", "BNBUSDT": "300",
+		"ETHBTC": "0.05", "BNBBTC": "0.0048", "BNBETH": "0.1",
+	}, nil)
+	// Each hop spends what the previous one received, a direct buy keeps headroom for the price.
+	first := mockClient.On("CreateOrder", "BNBBTC", "BUY", "208.12", mock.Anything).Return(filledOrder(1, "208.12", "0.998976"), nil)
+	second := mockClient.On("CreateOrder", "BNBETH", "SELL", "208.12", mock.Anything).Return(filledOrder(2, "208.12", "20.812"), nil).
+		NotBefore(first)
+	mockClient.On("CreateOrder", "ETHBTC", "SELL", "20.812", mock.Anything).Return(filledOrder(3, "20.812", "1.0406"), nil).
+		NotBefore(second)
+
+	strategy := &MultiHopStrategy{lastUsedCoinSymbol: "BTC"}
+	require.NoError(t, strategy.Scout(ctx))
+
+	mockClient.AssertExpectations(t)
+	assert.Equal(t, "BTC", strategy.lastUsedCoinSymbol)
+
+	var trades []models.Trade
+	require.NoError(t, ctx.DB.Order("id").Find(&trades).Error)
+	require.Len(t, trades, 3)
+	assert.Equal(t, []string{"BNBBTC", "BNBETH", "ETHBTC"}, []string{trades[0].Symbol, trades[1].Symbol, trades[2].Symbol})
+	assert.Zero(t, trades[0].Profit)
+	assert.InDelta(t, 0.0417, trades[2].Profit, 1e-4)
+
+	// Every hop is a jump of the same route.
+	var jumps []models.Jump
+	require.NoError(t, ctx.DB.Order("id").Find(&jumps).Error)
+	require.Len(t, jumps, 3)
+	for _, jump := range jumps {
+		assert.Equal(t, models.JumpStateDone, jump.State)
+		assert.Equal(t, jumps[0].RouteID, jump.RouteID)
+	}
+	assert.NotEmpty(t, jumps[0].RouteID)
+	assert.Equal(t, "BNB", jumps[0].ToCoinSymbol)
+}
+
+func TestExecuteRoute_HopWithUnknownOutcomeIsLeftToRecovery(t *testing.T) {
+	ctx, mockClient, quotes := newGraphTestContext(t, 0)
+	route, _, err := findBestRoute(ctx, "BTC", quotes, 3)
+	require.NoError(t, err)
+	require.Equal(t, "BTC>BNB>ETH>BTC", route.String())
+
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{
+		"BTCUSDT": "60000", "ETHUSDT": "3000", "BNBUSDT": "300",
+		"ETHBTC": "0.05", "BNBBTC": "0.0048", "BNBETH": "0.1",
+	}, nil)
+	mockClient.On("CreateOrder", "BNBBTC", "BUY", "208.12", mock.Anything).Return(filledOrder(1, "208.12", "0.998976"), nil)
+	mockClient.On("CreateOrder", "BNBETH", "SELL", "208.12", mock.Anything).
+		Return((*binance.CreateOrderResponse)(nil), fmt.Errorf("failed to create order: %w: timeout", binance.ErrOrderStatusUnknown))
+
+	held, err := executeRoute(ctx, route, dec("1"), 0.04, quotes)
+	assert.ErrorIs(t, err, binance.ErrOrderStatusUnknown)
+	assert.Empty(t, held, "the funds are not in any coin until the hop is settled")
+
+	current, err := lastCurrentCoin(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "BNB", current)
+
+	// Recovery finds the order filled and the route stops in ETH.
+	mockClient.On("GetOrderByClientID", "BNBETH", mock.Anything).Return(&binance.OrderResponse{
+		OrderID: 2, Status: binance.OrderStatusFilled, ExecutedQuantity: "208.12", CummulativeQuoteQty: "20.812",
+	}, nil)
+	settled, pending, err := RecoverJumps(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+	require.Len(t, settled, 1)
+	assert.Equal(t, "ETH", settled[0].HeldCoin())
+	assert.NotEmpty(t, settled[0].RouteID)
+
+	strategy := &MultiHopStrategy{lastUsedCoinSymbol: "BTC"}
+	strategy.OnJumpSettled(settled[0])
+	assert.Equal(t, "ETH", strategy.lastUsedCoinSymbol)
+	current, err = lastCurrentCoin(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", current)
+
+	// The route ends in ETH, so the ratios are re-baselined around it.
+	var pair models.Pair
+	require.NoError(t, ctx.DB.Where("from_coin_symbol = ? AND to_coin_symbol = ?", "BTC", "ETH").First(&pair).Error)
+	assert.Equal(t, "20", pair.Ratio.String())
+}
+
+func TestExecuteRoute_FailedBuyOutOfTheBridgeIsRolledBackByRecovery(t *testing.T) {
+	ctx, mockClient, quotes := newGraphTestContext(t, 0)
+	ctx.Cfg.Trading.JumpMaxBuyAttempts = 1
+	graph, err := buildCoinGraph(ctx, quotes)
+	require.NoError(t, err)
+	var sell, buy graphEdge
+	for _, e := range graph.edges {
+		if e.Symbol == "BTCUSDT" && e.Side == binance.OrderSideSell {
+			sell = e
+		}
+		if e.Symbol == "ETHUSDT" && e.Side == binance.OrderSideBuy {
+			buy = e
+		}
+	}
+	route := &tradeRoute{Legs: []graphEdge{sell, buy}, Rate: sell.Rate.Mul(buy.Rate)}
+
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3000"}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil).Once()
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "20", mock.Anything).
+		Return((*binance.CreateOrderResponse)(nil), errors.New("insufficient balance"))
+
+	held, err := executeRoute(ctx, route, dec("1"), 0.01, quotes)
+	assert.ErrorContains(t, err, "insufficient balance")
+	assert.Empty(t, held, "the bridge is never the coin to scout from")
+
+	var jump models.Jump
+	require.NoError(t, ctx.DB.First(&jump).Error)
+	assert.Equal(t, models.JumpStateSold, jump.State)
+	assert.Equal(t, "ETH", jump.ToCoinSymbol)
+	assert.NotEmpty(t, jump.RouteID)
+
+	// The next tick rolls the jump back to the first coin.
+	mockClient.On("CreateOrder", "BTCUSDT", "BUY", "1", mock.Anything).Return(filledOrder(3, "1", "60000"), nil).Once()
+	settled, pending, err := RecoverJumps(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+	require.Len(t, settled, 1)
+	assert.Equal(t, models.JumpStateFailed, settled[0].State)
+	assert.Equal(t, "BTC", settled[0].HeldCoin())
+	mockClient.AssertExpectations(t)
+
+	var trades []models.Trade
+	require.NoError(t, ctx.DB.Order("id").Find(&trades).Error)
+	require.Len(t, trades, 2)
+	assert.Equal(t, "SELL", trades[0].Type)
+	assert.Equal(t, "BUY", trades[1].Type)
+	assert.Equal(t, "BTCUSDT", trades[1].Symbol)
+}
@@ -2,10 +2,22 @@ package trader
 
 import (
 	"binance-trade-bot-go/internal/models"
+	"errors"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
+func init() {
+	RegisterStrategy("MultipleCoins", func(params StrategyParams) (Strategy, error) {
+		// The strategy has no parameters, any given is a mistake.
+		if err := params.Decode(&struct{}{}); err != nil {
+			return nil, err
+		}
+		return &MultipleCoinsStrategy{}, nil
+	})
+}
+
 // MultipleCoinsStrategy scouts all configured coins to find the best trading opportunity.
 type MultipleCoinsStrategy struct {
 	// This strategy is stateless, so it doesn't need to hold any data.
@@ -34,15 +46,15 @@ func (s *MultipleCoinsStrategy) Scout(ctx StrategyContext) error {
 	l := ctx.Logger.With(zap.String("strategy", s.Name()))
 	l.Info("Scouting for trades across all pairs...")
 
-	// 1. Get all ticker prices
-	prices, err := ctx.RestClient.GetAllTickerPrices()
+	// 1. Get the current quotes for the configured profit model
+	quotes, err := fetchQuotes(ctx)
 	if err != nil {
-		return fmt.Errorf("could not get all ticker prices: %w", err)
+		return err
 	}
 
 	// 2. Get all tradable pairs from the database
 	var allPairs []models.Pair
-	if err := ctx.DB.Find(&allPairs).Error; err != nil {
+	if err := ctx.DB.Where("enabled = ?", true).Find(&allPairs).Error; err != nil {
 		return fmt.Errorf("could not fetch pairs: %w", err)
 	}
 
@@ -51,14 +63,18 @@ func (s *MultipleCoinsStrategy) Scout(ctx StrategyContext) error {
 	// 3. Find the best opportunity among all pairs
 	for _, pair := range allPairs {
 		currentPair := pair
-		profit, err := calculateProfitForPair(ctx, &currentPair, prices)
+		profit, err := calculateProfitForPair(ctx, &currentPair, quotes)
+		if errors.Is(err, errPairNotTrading) {
+			l.Debug("Skipping pair", zap.String("pair", currentPair.FromCoinSymbol+"/"+currentPair.ToCoinSymbol), zap.Error(err))
+			continue
+		}
 		if err != nil {
 			l.Warn("Failed to calculate profit for pair", zap.String("pair", currentPair.FromCoinSymbol+"/"+currentPair.ToCoinSymbol), zap.Error(err))
 			continue
 		}
 
-		if profit > 0 {
-			if bestOpp == nil || profit > bestOpp.Profit {
+		if profit.IsPositive() {
+			if bestOpp == nil || profit.GreaterThan(bestOpp.Profit) {
 				bestOpp = &tradeOpportunity{Pair: currentPair, Profit: profit}
 			}
 		}
@@ -69,7 +85,7 @@ func (s *MultipleCoinsStrategy) Scout(ctx StrategyContext) error {
 		l.Info("Found best overall jump opportunity",
 			zap.String("from", bestOpp.Pair.FromCoinSymbol),
 			zap.String("to", bestOpp.Pair.ToCoinSymbol),
-			zap.Float64("profit_margin", bestOpp.Profit))
+			zap.Stringer("profit_margin", bestOpp.Profit))
 
 		// Find the quantity of the from coin
 		var fromCoin models.Coin
@@ -77,15 +93,18 @@ func (s *MultipleCoinsStrategy) Scout(ctx StrategyContext) error {
 			return fmt.Errorf("could not find from_coin %s in db: %w", bestOpp.Pair.FromCoinSymbol, err)
 		}
 
+		// Sell the whole free balance of the from coin
+		quantity, err := jumpQuantity(ctx, &fromCoin, decimal.Zero)
+		if err != nil {
+			return err
+		}
+
 		// Execute the jump
-		err := ExecuteJump(ctx, &bestOpp.Pair, fromCoin.Quantity, bestOpp.Profit)
+		err = ExecuteJump(ctx, &bestOpp.Pair, quantity, bestOpp.Profit.InexactFloat64(), quotes)
 		if err != nil {
 			l.Error("Failed to execute best jump", zap.Error(err))
 			return err
 		}
-		// The state update (e.g., coin quantities) should ideally be handled by a
-		// separate portfolio manager that listens to trade events.
-		// For now, we assume the core trading logic is complete after the jump.
 		l.Info("Successfully executed jump.")
 	} else {
 		l.Info("No profitable jump opportunities found in this cycle.")
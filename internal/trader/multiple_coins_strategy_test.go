@@ -5,6 +5,7 @@ import (
 	"binance-trade-bot-go/internal/config"
 	"binance-trade-bot-go/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 	"testing"
 )
@@ -12,10 +13,10 @@ import (
 func TestMultipleCoinsStrategy_Scout_NoOpportunity(t *testing.T) {
 	// Arrange
 	db, mockClient := setupTest(t)
-	db.Create(&models.Coin{Symbol: "BTC", Quantity: 1.0, Enabled: true})
-	db.Create(&models.Coin{Symbol: "ETH", Quantity: 15.0, Enabled: true})
-	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 15.0, MinQty: 0.01})
-	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: 1.0 / 15.0, MinQty: 0.0001})
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1"), Enabled: true})
+	db.Create(&models.Coin{Symbol: "ETH", Quantity: dec("15"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
+	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: dec("1").Div(dec("15")), MinQty: dec("0.0001")})
 
 	strategy := MultipleCoinsStrategy{}
 	ctx := StrategyContext{
@@ -47,10 +48,10 @@ func TestMultipleCoinsStrategy_Scout_NoOpportunity(t *testing.T) {
 func TestMultipleCoinsStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 	// Arrange
 	db, mockClient := setupTest(t)
-	db.Create(&models.Coin{Symbol: "BTC", Quantity: 1.0, Enabled: true})
-	db.Create(&models.Coin{Symbol: "ETH", Quantity: 15.0, Enabled: true})
-	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 15.0, MinQty: 0.01})
-	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: 1.0 / 15.0, MinQty: 0.0001})
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1"), Enabled: true})
+	db.Create(&models.Coin{Symbol: "ETH", Quantity: dec("15"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
+	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: dec("1").Div(dec("15")), MinQty: dec("0.0001")})
 
 	strategy := MultipleCoinsStrategy{}
 	ctx := StrategyContext{
@@ -64,10 +65,10 @@ func TestMultipleCoinsStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 		},
 		RestClient: mockClient,
 		DB:         db,
-		ExchangeRules: map[string]binance.SymbolInfo{
-			"BTCUSDT": {Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
-			"ETHUSDT": {Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"}}},
-		},
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
+			"ETHUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"}}},
+		}),
 	}
 
 	// Expect a call to get prices, and return prices that ARE profitable for BTC -> ETH
@@ -78,9 +79,9 @@ func TestMultipleCoinsStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 
 	// Expect the two-step jump:
 	// 1. Sell BTC for USDT
-	mockClient.On("CreateOrder", "BTCUSDT", "SELL", 1.0).Return(&binance.CreateOrderResponse{OrderID: 1}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
 	// 2. Buy ETH with USDT
-	mockClient.On("CreateOrder", "ETHUSDT", "BUY", 15.38).Return(&binance.CreateOrderResponse{OrderID: 2}, nil)
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "15.38", mock.Anything).Return(filledOrder(2, "15.38", "59982"), nil)
 
 	// Act
 	err := strategy.Scout(ctx)
@@ -93,13 +94,13 @@ func TestMultipleCoinsStrategy_Scout_ProfitableTrade_Success(t *testing.T) {
 func TestMultipleCoinsStrategy_Scout_SelectsBestOpportunity(t *testing.T) {
 	// Arrange
 	db, mockClient := setupTest(t)
-	db.Create(&models.Coin{Symbol: "BTC", Quantity: 1.0, Enabled: true})
-	db.Create(&models.Coin{Symbol: "ETH", Quantity: 15.0, Enabled: true})
-	db.Create(&models.Coin{Symbol: "LTC", Quantity: 200.0, Enabled: true})
-	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 15.0, MinQty: 0.01})
-	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: 1.0 / 15.0, MinQty: 0.0001})
-	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "LTC", Ratio: 200.0, MinQty: 0.1})
-	db.Create(&models.Pair{FromCoinSymbol: "LTC", ToCoinSymbol: "BTC", Ratio: 1.0 / 200.0, MinQty: 0.00001})
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1"), Enabled: true})
+	db.Create(&models.Coin{Symbol: "ETH", Quantity: dec("15"), Enabled: true})
+	db.Create(&models.Coin{Symbol: "LTC", Quantity: dec("200"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
+	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: dec("1").Div(dec("15")), MinQty: dec("0.0001")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "LTC", Ratio: dec("200"), MinQty: dec("0.1")})
+	db.Create(&models.Pair{FromCoinSymbol: "LTC", ToCoinSymbol: "BTC", Ratio: dec("1").Div(dec("200")), MinQty: dec("0.00001")})
 
 	strategy := MultipleCoinsStrategy{}
 	ctx := StrategyContext{
@@ -113,11 +114,11 @@ func TestMultipleCoinsStrategy_Scout_SelectsBestOpportunity(t *testing.T) {
 		},
 		RestClient: mockClient,
 		DB:         db,
-		ExchangeRules: map[string]binance.SymbolInfo{
-			"BTCUSDT": {Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
-			"ETHUSDT": {Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"}}},
-			"LTCUSDT": {Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.1", MinQty: "0.1"}}},
-		},
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
+			"ETHUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"}}},
+			"LTCUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.1", MinQty: "0.1"}}},
+		}),
 	}
 
 	// Both ETH and LTC are profitable, but LTC is MORE profitable.
@@ -128,8 +129,51 @@ func TestMultipleCoinsStrategy_Scout_SelectsBestOpportunity(t *testing.T) {
 	}, nil)
 
 	// Expect a jump to LTC, not ETH
-	mockClient.On("CreateOrder", "BTCUSDT", "SELL", 1.0).Return(&binance.CreateOrderResponse{OrderID: 1}, nil)
-	mockClient.On("CreateOrder", "LTCUSDT", "BUY", 206.8).Return(&binance.CreateOrderResponse{OrderID: 2}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
+	mockClient.On("CreateOrder", "LTCUSDT", "BUY", "206.8", mock.Anything).Return(filledOrder(2, "206.8", "59972"), nil)
+
+	// Act
+	err := strategy.Scout(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestMultipleCoinsStrategy_Scout_SizesJumpFromPortfolio(t *testing.T) {
+	// Arrange
+	db, mockClient := setupTest(t)
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1"), Enabled: true})
+	db.Create(&models.Coin{Symbol: "ETH", Quantity: dec("15"), Enabled: true})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
+
+	strategy := MultipleCoinsStrategy{}
+	ctx := StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT"}},
+		RestClient: mockClient,
+		DB:         db,
+		Portfolio:  NewPortfolio(),
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
+			"ETHUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"}}},
+		}),
+	}
+
+	// Part of the BTC is locked in an open order, only the free amount may be sold
+	mockClient.On("GetAccount").Return(&binance.AccountResponse{Balances: []binance.Balance{
+		{Asset: "BTC", Free: "0.5", Locked: "0.5"},
+	}}, nil).Once()
+	assert.NoError(t, ctx.Portfolio.Sync(ctx))
+
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900"}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "0.5", mock.Anything).Return(filledOrder(1, "0.5", "30000"), nil)
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "7.69", mock.Anything).Return(filledOrder(2, "7.69", "29991"), nil)
+	// The portfolio is refreshed once the jump is done
+	mockClient.On("GetAccount").Return(&binance.AccountResponse{Balances: []binance.Balance{
+		{Asset: "BTC", Free: "0", Locked: "0.5"},
+		{Asset: "ETH", Free: "7.68231", Locked: "0"},
+	}}, nil).Once()
 
 	// Act
 	err := strategy.Scout(ctx)
@@ -137,4 +181,5 @@ func TestMultipleCoinsStrategy_Scout_SelectsBestOpportunity(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
+	assert.Equal(t, "7.68231", ctx.Portfolio.Balance("ETH").Free.String())
 }
@@ -0,0 +1,180 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/models"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// InitializePairs makes the 'pairs' table match the enabled coins.
+// Every ordered pair between two enabled coins is created with a starting ratio
+// taken from the current bridge prices, and pairs involving a coin that is no
+// longer enabled are removed. Existing pairs keep their ratio, since it is the
+// benchmark the strategies compare against.
+func InitializePairs(ctx StrategyContext) error {
+	bridge := ctx.Cfg.Trading.Bridge
+
+	var coins []models.Coin
+	if err := ctx.DB.Where("enabled = ?", true).Find(&coins).Error; err != nil {
+		return fmt.Errorf("could not fetch enabled coins: %w", err)
+	}
+
+	enabled := make(map[string]struct{}, len(coins))
+	for _, c := range coins {
+		if c.Symbol != bridge {
+			enabled[c.Symbol] = struct{}{}
+		}
+	}
+
+	// Remove pairs for coins that have been disabled or removed. They are
+	// deleted permanently so the unique index allows them to be recreated.
+	var existing []models.Pair
+	if err := ctx.DB.Find(&existing).Error; err != nil {
+		return fmt.Errorf("could not fetch pairs: %w", err)
+	}
+	known := make(map[[2]string]struct{}, len(existing))
+	for _, p := range existing {
+		_, fromOk := enabled[p.FromCoinSymbol]
+		_, toOk := enabled[p.ToCoinSymbol]
+		if fromOk && toOk {
+			known[[2]string{p.FromCoinSymbol, p.ToCoinSymbol}] = struct{}{}
+			continue
+		}
+		if err := ctx.DB.Unscoped().Delete(&p).Error; err != nil {
+			return fmt.Errorf("could not remove pair %s/%s: %w", p.FromCoinSymbol, p.ToCoinSymbol, err)
+		}
+		ctx.Logger.Info("Removed pair of disabled coin", zap.String("from", p.FromCoinSymbol), zap.String("to", p.ToCoinSymbol))
+	}
+
+	var missing []models.Pair
+	for from := range enabled {
+		for to := range enabled {
+			if from == to {
+				continue
+			}
+			if _, ok := known[[2]string{from, to}]; !ok {
+				missing = append(missing, models.Pair{FromCoinSymbol: from, ToCoinSymbol: to})
+			}
+		}
+	}
+	if len(missing) == 0 {
+		ctx.Logger.Info("Trading pairs are up to date", zap.Int("pairs", len(known)))
+		return nil
+	}
+
+	prices, err := ctx.RestClient.GetAllTickerPrices(ctx.Context())
+	if err != nil {
+		return fmt.Errorf("could not get ticker prices for pair initialization: %w", err)
+	}
+
+	created := 0
+	for _, pair := range missing {
+		fromPrice, fromErr := parsePrice(prices, pair.FromCoinSymbol+bridge)
+		toPrice, toErr := parsePrice(prices, pair.ToCoinSymbol+bridge)
+		if fromErr != nil || toErr != nil {
+			ctx.Logger.Warn("Skipping pair without a valid bridge price",
+				zap.String("from", pair.FromCoinSymbol),
+				zap.String("to", pair.ToCoinSymbol))
+			continue
+		}
+
+		pair.Ratio = divRatio(fromPrice, toPrice)
+		pair.MinQty = lotSizeMinQty(ctx, pair.ToCoinSymbol+bridge)
+		if err := ctx.DB.Create(&pair).Error; err != nil {
+			return fmt.Errorf("could not create pair %s/%s: %w", pair.FromCoinSymbol, pair.ToCoinSymbol, err)
+		}
+		created++
+	}
+
+	ctx.Logger.Info("Initialized trading pairs", zap.Int("created", created), zap.Int("existing", len(known)))
+	return nil
+}
+
+// ratioPrecision is the number of decimal places ratios and rates are computed
+// to. Prices of cheap coins are small, so the default precision of a decimal
+// division would leave their ratios with only a few significant digits.
+const ratioPrecision = 32
+
+// divRatio returns a / b to ratioPrecision decimal places. It rounds down, so
+// a rate is never overstated and a round trip at fair prices never looks like
+// a gain.
+func divRatio(a, b decimal.Decimal) decimal.Decimal {
+	q, _ := a.QuoRem(b, ratioPrecision)
+	return q
+}
+
+// parsePrice returns the positive price of a symbol from a ticker price map.
+func parsePrice(prices map[string]string, symbol string) (decimal.Decimal, error) {
+	priceStr, ok := prices[symbol]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("price not available for %s", symbol)
+	}
+	price, err := decimal.NewFromString(priceStr)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to parse price for %s: %w", symbol, err)
+	}
+	if !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("invalid price for %s", symbol)
+	}
+	return price, nil
+}
+
+// lotSizeMinQty returns the LOT_SIZE minimum quantity of a symbol, or 0 if unknown.
+func lotSizeMinQty(ctx StrategyContext, symbol string) decimal.Decimal {
+	rule, ok := ctx.ExchangeRules.Symbol(symbol)
+	if !ok {
+		return decimal.Zero
+	}
+	filters, err := rule.ParseFilters()
+	if err != nil {
+		return decimal.Zero
+	}
+	return filters.LotSize.MinQty
+}
+
+// updateRatiosAfterJump re-baselines every pair involving coin after a jump into it.
+// Pairs into the coin get ratio from/coin and pairs out of it get coin/to, where the
+// coin is valued at the price the jump actually filled at and the other side at the
+// current bridge price. This mirrors the Python bot and stops the same pair from
+// looking profitable again immediately after the jump.
+func updateRatiosAfterJump(ctx StrategyContext, coin string, coinPrice decimal.Decimal, prices map[string]string) error {
+	if !coinPrice.IsPositive() {
+		return fmt.Errorf("invalid fill price %s for %s", coinPrice, coin)
+	}
+	bridge := ctx.Cfg.Trading.Bridge
+
+	var pairs []models.Pair
+	if err := ctx.DB.Where("from_coin_symbol = ? OR to_coin_symbol = ?", coin, coin).Find(&pairs).Error; err != nil {
+		return fmt.Errorf("could not get pairs for coin %s: %w", coin, err)
+	}
+
+	updated := 0
+	for _, pair := range pairs {
+		other := pair.FromCoinSymbol
+		if other == coin {
+			other = pair.ToCoinSymbol
+		}
+		otherPrice, err := parsePrice(prices, other+bridge)
+		if err != nil {
+			ctx.Logger.Warn("Could not update ratio, price unavailable",
+				zap.String("from", pair.FromCoinSymbol),
+				zap.String("to", pair.ToCoinSymbol),
+				zap.Error(err))
+			continue
+		}
+
+		ratio := divRatio(otherPrice, coinPrice)
+		if pair.FromCoinSymbol == coin {
+			ratio = divRatio(coinPrice, otherPrice)
+		}
+		if err := ctx.DB.Model(&pair).Update("ratio", ratio).Error; err != nil {
+			return fmt.Errorf("could not update ratio for pair %s/%s: %w", pair.FromCoinSymbol, pair.ToCoinSymbol, err)
+		}
+		updated++
+	}
+
+	ctx.Logger.Info("Updated pair ratios after jump", zap.String("coin", coin), zap.Stringer("price", coinPrice), zap.Int("pairs", updated))
+	return nil
+}
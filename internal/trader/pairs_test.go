@@ -0,0 +1,147 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestInitializePairs(t *testing.T) {
+	// Arrange
+	db, mockClient := setupTest(t)
+	db.Create(&models.Coin{Symbol: "BTC", Enabled: true})
+	db.Create(&models.Coin{Symbol: "ETH", Enabled: true})
+	db.Create(&models.Coin{Symbol: "LTC", Enabled: true})
+	db.Create(&models.Coin{Symbol: "BNB", Enabled: true})
+	db.Model(&models.Coin{}).Where("symbol = ?", "BNB").Update("enabled", false)
+	// An existing pair keeps its benchmark ratio, a pair of a disabled coin is removed.
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("12"), MinQty: dec("0.01")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "BNB", Ratio: dec("100"), MinQty: dec("0.01")})
+
+	ctx := StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT"}},
+		RestClient: mockClient,
+		DB:         db,
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
+			"ETHUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.0001", MinQty: "0.0001"}}},
+		}),
+	}
+
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{
+		"BTCUSDT": "60000",
+		"ETHUSDT": "4000",
+		"LTCUSDT": "100",
+		"BNBUSDT": "600",
+	}, nil).Once()
+
+	// Act
+	err := InitializePairs(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	var pairs []models.Pair
+	db.Find(&pairs)
+	assert.Len(t, pairs, 6)
+
+	ratios := make(map[string]models.Pair)
+	for _, p := range pairs {
+		ratios[p.FromCoinSymbol+"/"+p.ToCoinSymbol] = p
+	}
+	assert.NotContains(t, ratios, "BTC/BNB")
+	assert.Equal(t, "12", ratios["BTC/ETH"].Ratio.String())
+	assert.Equal(t, "0.06666666666666666666666666666666", ratios["ETH/BTC"].Ratio.String())
+	assert.Equal(t, "600", ratios["BTC/LTC"].Ratio.String())
+	assert.Equal(t, "40", ratios["ETH/LTC"].Ratio.String())
+	assert.Equal(t, "0.00001", ratios["ETH/BTC"].MinQty.String())
+	assert.Equal(t, "0.0001", ratios["LTC/ETH"].MinQty.String())
+	assert.Equal(t, "0", ratios["BTC/LTC"].MinQty.String())
+
+	// Running again with nothing missing does not hit the exchange.
+	assert.NoError(t, InitializePairs(ctx))
+	mockClient.AssertNumberOfCalls(t, "GetAllTickerPrices", 1)
+}
+
+func TestUpdateRatiosAfterJump(t *testing.T) {
+	// Arrange
+	db, _ := setupTest(t)
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15")})
+	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: dec("1").Div(dec("15"))})
+	db.Create(&models.Pair{FromCoinSymbol: "LTC", ToCoinSymbol: "ETH", Ratio: dec("0.05")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "LTC", Ratio: dec("500")})
+
+	ctx := StrategyContext{
+		Logger: zap.NewNop(),
+		Cfg:    &config.Config{Trading: config.Trading{Bridge: "USDT"}},
+		DB:     db,
+	}
+	prices := map[string]string{"BTCUSDT": "60000", "ETHUSDT": "3900", "LTCUSDT": "100"}
+
+	// Act: we jumped into ETH and the buy filled at 4000
+	err := updateRatiosAfterJump(ctx, "ETH", dec("4000"), prices)
+
+	// Assert
+	assert.NoError(t, err)
+	ratio := func(from, to string) string {
+		var p models.Pair
+		db.Where("from_coin_symbol = ? AND to_coin_symbol = ?", from, to).First(&p)
+		return p.Ratio.String()
+	}
+	assert.Equal(t, "15", ratio("BTC", "ETH"))
+	assert.Equal(t, "0.06666666666666666666666666666666", ratio("ETH", "BTC"))
+	assert.Equal(t, "0.025", ratio("LTC", "ETH"))
+	assert.Equal(t, "500", ratio("BTC", "LTC")) // does not involve ETH
+
+	assert.Error(t, updateRatiosAfterJump(ctx, "ETH", decimal.Zero, prices))
+}
+
+func TestExecuteJump_UpdatesRatiosFromFillPrice(t *testing.T) {
+	// Arrange
+	db, mockClient := setupTest(t)
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
+	db.Create(&models.Pair{FromCoinSymbol: "ETH", ToCoinSymbol: "BTC", Ratio: dec("1").Div(dec("15")), MinQty: dec("0.00001")})
+
+	strategy := DefaultStrategy{lastUsedCoinSymbol: "BTC"}
+	ctx := StrategyContext{
+		Logger: zap.NewNop(),
+		Cfg: &config.Config{
+			Trading: config.Trading{Quantity: 1.0, Bridge: "USDT"},
+		},
+		RestClient: mockClient,
+		DB:         db,
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.00001", MinQty: "0.00001"}}},
+			"ETHUSDT": {Status: binance.SymbolStatusTrading, Filters: []binance.Filter{{FilterType: "LOT_SIZE", StepSize: "0.01", MinQty: "0.001"}}},
+		}),
+	}
+
+	mockClient.On("GetAllTickerPrices").Return(map[string]string{
+		"BTCUSDT": "60000",
+		"ETHUSDT": "3900",
+	}, nil)
+	mockClient.On("CreateOrder", "BTCUSDT", "SELL", "1", mock.Anything).Return(filledOrder(1, "1", "60000"), nil)
+	// The buy fills slightly above the ticker price.
+	mockClient.On("CreateOrder", "ETHUSDT", "BUY", "15.38", mock.Anything).Return(filledOrder(2, "15.38", "60135.8"), nil) // 3910 per ETH
+
+	// Act
+	err := strategy.Scout(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+
+	var btcEth, ethBtc models.Pair
+	db.Where("from_coin_symbol = ? AND to_coin_symbol = ?", "BTC", "ETH").First(&btcEth)
+	assert.Equal(t, "15.34526854219948849104859335038363", btcEth.Ratio.String()) // 60000 / 3910
+	db.Where("from_coin_symbol = ? AND to_coin_symbol = ?", "ETH", "BTC").First(&ethBtc)
+	assert.Equal(t, "0.06516666666666666666666666666666", ethBtc.Ratio.String()) // 3910 / 60000
+}
@@ -0,0 +1,141 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/models"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultPortfolioSyncInterval applies when trading.portfolio_sync_interval is not set.
+const defaultPortfolioSyncInterval = 60 * time.Second
+
+// AssetBalance is the amount of an asset held on the account.
+type AssetBalance struct {
+	Free   decimal.Decimal `json:"free"`   // Available for trading
+	Locked decimal.Decimal `json:"locked"` // Held by open orders
+}
+
+// Total returns the free and locked amount together.
+func (b AssetBalance) Total() decimal.Decimal {
+	return b.Free.Add(b.Locked)
+}
+
+// Portfolio mirrors the account balances on the exchange. It is refreshed by
+// Sync, which also writes the free and locked amounts of every coin to the
+// database, and is safe for concurrent use.
+type Portfolio struct {
+	mu        sync.RWMutex
+	balances  map[string]AssetBalance
+	updatedAt time.Time
+}
+
+// NewPortfolio creates an empty portfolio. Call Sync to load the balances.
+func NewPortfolio() *Portfolio {
+	return &Portfolio{balances: make(map[string]AssetBalance)}
+}
+
+// Sync reads the account balances from the exchange and updates the coin quantities.
+func (p *Portfolio) Sync(ctx StrategyContext) error {
+	account, err := ctx.RestClient.GetAccount(ctx.Context())
+	if err != nil {
+		return fmt.Errorf("could not get account balances: %w", err)
+	}
+
+	balances := make(map[string]AssetBalance, len(account.Balances))
+	for _, b := range account.Balances {
+		free, err := decimal.NewFromString(b.Free)
+		if err != nil {
+			return fmt.Errorf("invalid free balance %q for %s: %w", b.Free, b.Asset, err)
+		}
+		locked, err := decimal.NewFromString(b.Locked)
+		if err != nil {
+			return fmt.Errorf("invalid locked balance %q for %s: %w", b.Locked, b.Asset, err)
+		}
+		balances[b.Asset] = AssetBalance{Free: free, Locked: locked}
+	}
+
+	err = ctx.DB.Transaction(func(tx *gorm.DB) error {
+		var coins []models.Coin
+		if err := tx.Find(&coins).Error; err != nil {
+			return err
+		}
+		for _, c := range coins {
+			b := balances[c.Symbol]
+			// Update with a map so balances that dropped to zero are written too.
+			if err := tx.Model(&c).Updates(map[string]interface{}{"quantity": b.Free, "locked": b.Locked}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not update coin quantities: %w", err)
+	}
+
+	p.mu.Lock()
+	p.balances = balances
+	p.updatedAt = ctx.clock().Now()
+	p.mu.Unlock()
+
+	ctx.Logger.Debug("Portfolio synced", zap.Int("assets", len(balances)))
+	return nil
+}
+
+// Balance returns the balance of a single asset. Assets that are not held
+// have a zero balance.
+func (p *Portfolio) Balance(asset string) AssetBalance {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.balances[asset]
+}
+
+// Balances returns a copy of all non-zero balances.
+func (p *Portfolio) Balances() map[string]AssetBalance {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	balances := make(map[string]AssetBalance, len(p.balances))
+	for asset, b := range p.balances {
+		balances[asset] = b
+	}
+	return balances
+}
+
+// UpdatedAt returns the time of the last successful sync.
+func (p *Portfolio) UpdatedAt() time.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.updatedAt
+}
+
+// syncPortfolio refreshes the portfolio after a trade. A failure is only
+// logged, since the next periodic sync will catch up.
+func syncPortfolio(ctx StrategyContext) {
+	if ctx.Portfolio == nil {
+		return
+	}
+	if err := ctx.Portfolio.Sync(ctx); err != nil {
+		ctx.Logger.Warn("Failed to sync portfolio", zap.Error(err))
+	}
+}
+
+// jumpQuantity returns how much of a coin a jump should sell: the free balance
+// from the portfolio, or the synced coin quantity when no portfolio is
+// available, capped at limit when limit is positive.
+func jumpQuantity(ctx StrategyContext, coin *models.Coin, limit decimal.Decimal) (decimal.Decimal, error) {
+	available := coin.Quantity
+	if ctx.Portfolio != nil {
+		available = ctx.Portfolio.Balance(coin.Symbol).Free
+	}
+	if !available.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no free %s balance to trade", coin.Symbol)
+	}
+	if limit.IsPositive() && limit.LessThan(available) {
+		return limit, nil
+	}
+	return available, nil
+}
@@ -0,0 +1,87 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPortfolio_Sync(t *testing.T) {
+	db, mockClient := setupTest(t)
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("5")})
+	db.Create(&models.Coin{Symbol: "ETH", Quantity: dec("2")})
+	ctx := StrategyContext{Logger: zap.NewNop(), Cfg: &config.Config{}, RestClient: mockClient, DB: db}
+
+	mockClient.On("GetAccount").Return(&binance.AccountResponse{Balances: []binance.Balance{
+		{Asset: "BTC", Free: "0.75", Locked: "0.25"},
+		{Asset: "USDT", Free: "100", Locked: "0"},
+	}}, nil)
+
+	portfolio := NewPortfolio()
+	require.NoError(t, portfolio.Sync(ctx))
+
+	assert.Equal(t, AssetBalance{Free: dec("0.75"), Locked: dec("0.25")}, portfolio.Balance("BTC"))
+	assert.Equal(t, "1", portfolio.Balance("BTC").Total().String())
+	assert.Equal(t, AssetBalance{}, portfolio.Balance("ETH"))
+	assert.Len(t, portfolio.Balances(), 2)
+	assert.False(t, portfolio.UpdatedAt().IsZero())
+
+	var btc, eth models.Coin
+	db.Where("symbol = ?", "BTC").First(&btc)
+	db.Where("symbol = ?", "ETH").First(&eth)
+	assert.Equal(t, "0.75", btc.Quantity.String())
+	assert.Equal(t, "0.25", btc.Locked.String())
+	// Coins that are no longer held are zeroed.
+	assert.Equal(t, "0", eth.Quantity.String())
+}
+
+func TestPortfolio_SyncErrorKeepsLastBalances(t *testing.T) {
+	db, mockClient := setupTest(t)
+	ctx := StrategyContext{Logger: zap.NewNop(), Cfg: &config.Config{}, RestClient: mockClient, DB: db}
+
+	mockClient.On("GetAccount").Return(&binance.AccountResponse{Balances: []binance.Balance{{Asset: "BTC", Free: "1", Locked: "0"}}}, nil).Once()
+	mockClient.On("GetAccount").Return((*binance.AccountResponse)(nil), errors.New("timeout")).Once()
+
+	portfolio := NewPortfolio()
+	require.NoError(t, portfolio.Sync(ctx))
+	assert.Error(t, portfolio.Sync(ctx))
+	assert.Equal(t, "1", portfolio.Balance("BTC").Free.String())
+}
+
+func TestJumpQuantity(t *testing.T) {
+	ctx := StrategyContext{Logger: zap.NewNop(), Cfg: &config.Config{}}
+	coin := &models.Coin{Symbol: "BTC", Quantity: dec("2")}
+
+	t.Run("Falls back to the synced coin quantity", func(t *testing.T) {
+		qty, err := jumpQuantity(ctx, coin, decimal.Zero)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", qty.String())
+	})
+
+	ctx.Portfolio = NewPortfolio()
+	ctx.Portfolio.balances["BTC"] = AssetBalance{Free: dec("0.5"), Locked: dec("1")}
+
+	t.Run("Uses the free balance from the portfolio", func(t *testing.T) {
+		qty, err := jumpQuantity(ctx, coin, decimal.Zero)
+		assert.NoError(t, err)
+		assert.Equal(t, "0.5", qty.String())
+	})
+
+	t.Run("Is capped by the limit", func(t *testing.T) {
+		qty, err := jumpQuantity(ctx, coin, dec("0.1"))
+		assert.NoError(t, err)
+		assert.Equal(t, "0.1", qty.String())
+	})
+
+	t.Run("Fails without a free balance", func(t *testing.T) {
+		_, err := jumpQuantity(ctx, &models.Coin{Symbol: "ETH", Quantity: dec("3")}, decimal.Zero)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,74 @@
+package trader
+
+import (
+	"context"
+	"sync/atomic"
+
+	"binance-trade-bot-go/internal/binance"
+	"go.uber.org/zap"
+)
+
+// PriceSource provides the market prices jumps are evaluated and sized at.
+// Both the REST client and the WebSocket stream client implement it.
+type PriceSource interface {
+	GetAllTickerPrices(ctx context.Context) (map[string]string, error)
+	GetAllBookTickers(ctx context.Context) (map[string]binance.BookTicker, error)
+}
+
+// FallbackPriceSource reads prices from a primary source, typically the
+// WebSocket stream, and falls back to a secondary one, typically REST, while
+// the primary fails, e.g. because the stream is stale.
+type FallbackPriceSource struct {
+	primary  PriceSource
+	fallback PriceSource
+	logger   *zap.Logger
+	degraded atomic.Bool
+}
+
+// NewFallbackPriceSource creates a price source that prefers primary over fallback.
+func NewFallbackPriceSource(primary, fallback PriceSource, logger *zap.Logger) *FallbackPriceSource {
+	return &FallbackPriceSource{primary: primary, fallback: fallback, logger: logger}
+}
+
+// GetAllTickerPrices returns the last prices from the first source that has them.
+func (s *FallbackPriceSource) GetAllTickerPrices(ctx context.Context) (map[string]string, error) {
+	prices, err := s.primary.GetAllTickerPrices(ctx)
+	if err == nil {
+		s.recovered()
+		return prices, nil
+	}
+	s.degrade(err)
+	return s.fallback.GetAllTickerPrices(ctx)
+}
+
+// GetAllBookTickers returns the book tickers from the first source that has them.
+func (s *FallbackPriceSource) GetAllBookTickers(ctx context.Context) (map[string]binance.BookTicker, error) {
+	tickers, err := s.primary.GetAllBookTickers(ctx)
+	if err == nil {
+		s.recovered()
+		return tickers, nil
+	}
+	s.degrade(err)
+	return s.fallback.GetAllBookTickers(ctx)
+}
+
+// degrade logs the switch to the fallback source once, not on every call.
+func (s *FallbackPriceSource) degrade(err error) {
+	if !s.degraded.Swap(true) {
+		s.logger.Warn("Primary price source unavailable, falling back", zap.Error(err))
+	}
+}
+
+func (s *FallbackPriceSource) recovered() {
+	if s.degraded.Swap(false) {
+		s.logger.Info("Primary price source recovered")
+	}
+}
+
+// priceSource returns the price source of the context, which defaults to the REST client.
+func (ctx StrategyContext) priceSource() PriceSource {
+	if ctx.Prices != nil {
+		return ctx.Prices
+	}
+	return ctx.RestClient
+}
@@ -0,0 +1,60 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFallbackPriceSource(t *testing.T) {
+	stream := new(MockRestClient)
+	rest := new(MockRestClient)
+	source := NewFallbackPriceSource(stream, rest, zap.NewNop())
+
+	// Fresh stream data is served without touching REST
+	stream.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60001"}, nil).Once()
+	prices, err := source.GetAllTickerPrices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "60001", prices["BTCUSDT"])
+
+	// A stale stream falls back to REST
+	stale := fmt.Errorf("%w: last update 12s ago", binance.ErrStreamStale)
+	stream.On("GetAllTickerPrices").Return(map[string]string(nil), stale).Once()
+	rest.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000"}, nil).Once()
+	prices, err = source.GetAllTickerPrices(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "60000", prices["BTCUSDT"])
+
+	stream.On("GetAllBookTickers").Return(map[string]binance.BookTicker(nil), stale).Once()
+	rest.On("GetAllBookTickers").Return(map[string]binance.BookTicker{"BTCUSDT": {BidPrice: "59990", AskPrice: "60010"}}, nil).Once()
+	books, err := source.GetAllBookTickers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "60010", books["BTCUSDT"].AskPrice)
+
+	stream.AssertExpectations(t)
+	rest.AssertExpectations(t)
+}
+
+func TestFetchQuotes_UsesContextPriceSource(t *testing.T) {
+	rest := new(MockRestClient)
+	stream := new(MockRestClient)
+	ctx := StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{ProfitModel: ProfitModelBidAsk}},
+		RestClient: rest,
+		Prices:     stream,
+	}
+	stream.On("GetAllBookTickers").Return(map[string]binance.BookTicker{"BTCUSDT": {BidPrice: "59990", AskPrice: "60010"}}, nil)
+
+	quotes, err := fetchQuotes(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, Quote{Bid: dec("59990"), Ask: dec("60010")}, quotes["BTCUSDT"])
+	rest.AssertNotCalled(t, "GetAllBookTickers")
+}
@@ -0,0 +1,93 @@
+package trader
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Profit models select which prices a jump is evaluated at.
+const (
+	// ProfitModelLastPrice evaluates both legs at the last traded price.
+	ProfitModelLastPrice = "last_price"
+	// ProfitModelBidAsk evaluates the sell leg at the best bid and the buy leg
+	// at the best ask, which is where market orders actually fill.
+	ProfitModelBidAsk = "bid_ask"
+)
+
+// Quote is the price a symbol can currently be sold (Bid) and bought (Ask) at.
+type Quote struct {
+	Bid decimal.Decimal
+	Ask decimal.Decimal
+}
+
+// Quotes maps a symbol to its quote.
+type Quotes map[string]Quote
+
+// fetchQuotes loads the current quotes for all symbols according to the
+// configured trading.profit_model. With the last price model the bid and the
+// ask are both the last traded price.
+func fetchQuotes(ctx StrategyContext) (Quotes, error) {
+	switch model := ctx.Cfg.Trading.ProfitModel; model {
+	case "", ProfitModelLastPrice:
+		prices, err := ctx.priceSource().GetAllTickerPrices(ctx.Context())
+		if err != nil {
+			return nil, fmt.Errorf("could not get all ticker prices: %w", err)
+		}
+		return quotesFromPrices(prices), nil
+	case ProfitModelBidAsk:
+		tickers, err := ctx.priceSource().GetAllBookTickers(ctx.Context())
+		if err != nil {
+			return nil, fmt.Errorf("could not get all book tickers: %w", err)
+		}
+		quotes := make(Quotes, len(tickers))
+		for symbol, t := range tickers {
+			bid, err1 := decimal.NewFromString(t.BidPrice)
+			ask, err2 := decimal.NewFromString(t.AskPrice)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			quotes[symbol] = Quote{Bid: bid, Ask: ask}
+		}
+		return quotes, nil
+	default:
+		return nil, fmt.Errorf("unknown profit model %q, expected %q or %q", model, ProfitModelLastPrice, ProfitModelBidAsk)
+	}
+}
+
+// quotesFromPrices builds quotes from last traded prices. Unparsable prices are skipped.
+func quotesFromPrices(prices map[string]string) Quotes {
+	quotes := make(Quotes, len(prices))
+	for symbol, p := range prices {
+		price, err := decimal.NewFromString(p)
+		if err != nil {
+			continue
+		}
+		quotes[symbol] = Quote{Bid: price, Ask: price}
+	}
+	return quotes
+}
+
+// sellPrice returns the price a market sell of symbol is expected to fill at.
+func (q Quotes) sellPrice(symbol string) (decimal.Decimal, error) {
+	quote, ok := q[symbol]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("price not available for %s", symbol)
+	}
+	if !quote.Bid.IsPositive() {
+		return decimal.Zero, fmt.Errorf("invalid bid price for %s", symbol)
+	}
+	return quote.Bid, nil
+}
+
+// buyPrice returns the price a market buy of symbol is expected to fill at.
+func (q Quotes) buyPrice(symbol string) (decimal.Decimal, error) {
+	quote, ok := q[symbol]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("price not available for %s", symbol)
+	}
+	if !quote.Ask.IsPositive() {
+		return decimal.Zero, fmt.Errorf("invalid ask price for %s", symbol)
+	}
+	return quote.Ask, nil
+}
@@ -0,0 +1,127 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFetchQuotes(t *testing.T) {
+	newCtx := func(model string) (StrategyContext, *MockRestClient) {
+		mockClient := new(MockRestClient)
+		return StrategyContext{
+			Logger:     zap.NewNop(),
+			Cfg:        &config.Config{Trading: config.Trading{ProfitModel: model}},
+			RestClient: mockClient,
+		}, mockClient
+	}
+
+	t.Run("Last price model quotes the last trade on both sides", func(t *testing.T) {
+		ctx, mockClient := newCtx(ProfitModelLastPrice)
+		mockClient.On("GetAllTickerPrices").Return(map[string]string{"BTCUSDT": "60000", "BADUSDT": "n/a"}, nil)
+
+		quotes, err := fetchQuotes(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, Quotes{"BTCUSDT": {Bid: dec("60000"), Ask: dec("60000")}}, quotes)
+	})
+
+	t.Run("Bid/ask model reads the order books", func(t *testing.T) {
+		ctx, mockClient := newCtx(ProfitModelBidAsk)
+		mockClient.On("GetAllBookTickers").Return(map[string]binance.BookTicker{
+			"BTCUSDT": {Symbol: "BTCUSDT", BidPrice: "59990", AskPrice: "60010"},
+		}, nil)
+
+		quotes, err := fetchQuotes(ctx)
+
+		require.NoError(t, err)
+		mockClient.AssertExpectations(t)
+		assert.Equal(t, Quotes{"BTCUSDT": {Bid: dec("59990"), Ask: dec("60010")}}, quotes)
+	})
+
+	t.Run("Unknown model is rejected", func(t *testing.T) {
+		ctx, _ := newCtx("mid")
+
+		_, err := fetchQuotes(ctx)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "mid")
+	})
+}
+
+func TestCalculateProfitForPair_WideSpread(t *testing.T) {
+	ctx := StrategyContext{
+		Logger: zap.NewNop(),
+		Cfg:    &config.Config{Trading: config.Trading{Bridge: "USDT", FeeRate: 0.001}},
+	}
+	pair := models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15")}
+
+	testCases := []struct {
+		name           string
+		quotes         Quotes
+		expectedProfit float64
+	}{
+		{
+			name:   "Last prices look profitable",
+			quotes: Quotes{"BTCUSDT": {Bid: dec("60000"), Ask: dec("60000")}, "ETHUSDT": {Bid: dec("3900"), Ask: dec("3900")}},
+			// 60000/3900 = 15.385 -> (15.385 * 0.999^2) / 15 - 1 ~= 2.35%
+			expectedProfit: 0.0236,
+		},
+		{
+			name:   "Wide spread on both books turns the jump into a loss",
+			quotes: Quotes{"BTCUSDT": {Bid: dec("59000"), Ask: dec("61000")}, "ETHUSDT": {Bid: dec("3800"), Ask: dec("4000")}},
+			// 59000/4000 = 14.75 -> (14.75 * 0.999^2) / 15 - 1 ~= -1.86%
+			expectedProfit: -0.0186,
+		},
+		{
+			name:   "Spread on the buy side alone can eat the profit",
+			quotes: Quotes{"BTCUSDT": {Bid: dec("60000"), Ask: dec("60010")}, "ETHUSDT": {Bid: dec("3900"), Ask: dec("3995")}},
+			// 60000/3995 = 15.019 -> (15.019 * 0.999^2) / 15 - 1 ~= -0.07%
+			expectedProfit: -0.0007,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			profit, err := calculateProfitForPair(ctx, &pair, tc.quotes)
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expectedProfit, profit.InexactFloat64(), 0.0005)
+		})
+	}
+}
+
+func TestDefaultStrategy_Scout_BidAskSkipsSpreadLosses(t *testing.T) {
+	// Arrange
+	db, mockClient := setupTest(t)
+	db.Create(&models.Coin{Symbol: "BTC", Quantity: dec("1")})
+	db.Create(&models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15"), MinQty: dec("0.01")})
+
+	strategy := DefaultStrategy{lastUsedCoinSymbol: "BTC"}
+	ctx := StrategyContext{
+		Logger:     zap.NewNop(),
+		Cfg:        &config.Config{Trading: config.Trading{Bridge: "USDT", Quantity: 1.0, FeeRate: 0.001, ProfitModel: ProfitModelBidAsk}},
+		RestClient: mockClient,
+		DB:         db,
+	}
+
+	// The mid prices (60000 / 3900) would trigger a jump, but the books are too wide
+	mockClient.On("GetAllBookTickers").Return(map[string]binance.BookTicker{
+		"BTCUSDT": {Symbol: "BTCUSDT", BidPrice: "59000", AskPrice: "61000"},
+		"ETHUSDT": {Symbol: "ETHUSDT", BidPrice: "3800", AskPrice: "4000"},
+	}, nil)
+
+	// Act
+	err := strategy.Scout(ctx)
+
+	// Assert
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "CreateOrder")
+}
@@ -0,0 +1,82 @@
+package trader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// StrategyParams is the trading.strategy_params section of the config, the
+// parameters of the configured strategy.
+type StrategyParams map[string]interface{}
+
+// Decode copies the parameters into target, a pointer to the strategy's own
+// parameter struct whose fields are tagged with `mapstructure`. Parameters the
+// struct does not know are rejected, so a typo is not silently ignored.
+func (p StrategyParams) Decode(target interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused:      true,
+		WeaklyTypedInput: true,
+		Result:           target,
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(map[string]interface{}(p)); err != nil {
+		return fmt.Errorf("invalid strategy_params: %w", err)
+	}
+	return nil
+}
+
+// StrategyFactory creates a strategy from its parameters.
+type StrategyFactory func(params StrategyParams) (Strategy, error)
+
+var (
+	strategiesMu sync.RWMutex
+	strategies   = make(map[string]StrategyFactory)
+)
+
+// RegisterStrategy makes a strategy available under name, the value of
+// trading.strategy that selects it. It is meant to be called from an init
+// function and panics if the name is already taken or the factory is nil.
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	if factory == nil {
+		panic("trader: RegisterStrategy factory is nil for " + name)
+	}
+	if _, dup := strategies[name]; dup {
+		panic("trader: RegisterStrategy called twice for " + name)
+	}
+	strategies[name] = factory
+}
+
+// StrategyNames returns the names of all registered strategies, sorted.
+func StrategyNames() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewStrategy creates the strategy registered under name with its parameters.
+func NewStrategy(name string, params StrategyParams) (Strategy, error) {
+	strategiesMu.RLock()
+	factory, ok := strategies[name]
+	strategiesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q, valid strategies are: %s", name, strings.Join(StrategyNames(), ", "))
+	}
+	strategy, err := factory(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not create strategy %s: %w", name, err)
+	}
+	return strategy, nil
+}
@@ -0,0 +1,60 @@
+package trader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStrategy(t *testing.T) {
+	assert.Subset(t, StrategyNames(), []string{"Default", "MultiHop", "MultipleCoins"})
+
+	strategy, err := NewStrategy("MultipleCoins", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "MultipleCoins", strategy.Name())
+
+	_, err = NewStrategy("Momentum", nil)
+	assert.ErrorContains(t, err, `unknown strategy "Momentum", valid strategies are: `)
+	assert.ErrorContains(t, err, "Default, MultiHop, MultipleCoins")
+}
+
+func TestNewStrategy_DecodesParams(t *testing.T) {
+	strategy, err := NewStrategy("MultiHop", nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxHops, strategy.(*MultiHopStrategy).maxHops)
+
+	// Values from environment variables arrive as strings.
+	strategy, err = NewStrategy("MultiHop", StrategyParams{"max_hops": "2"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, strategy.(*MultiHopStrategy).maxHops)
+
+	_, err = NewStrategy("MultiHop", StrategyParams{"max_hop": 2})
+	assert.ErrorContains(t, err, "max_hop")
+
+	_, err = NewStrategy("MultiHop", StrategyParams{"max_hops": 0})
+	assert.ErrorContains(t, err, "max_hops must be at least 1")
+}
+
+func TestNewStrategy_RejectsUnknownParams(t *testing.T) {
+	for _, name := range []string{"Default", "MultiHop", "MultipleCoins"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewStrategy(name, StrategyParams{"scout_margn": 1})
+			assert.ErrorContains(t, err, "scout_margn")
+		})
+	}
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	factory := func(StrategyParams) (Strategy, error) { return &DefaultStrategy{}, nil }
+	RegisterStrategy("TestRegisterStrategy", factory)
+	t.Cleanup(func() {
+		strategiesMu.Lock()
+		delete(strategies, "TestRegisterStrategy")
+		strategiesMu.Unlock()
+	})
+
+	assert.Contains(t, StrategyNames(), "TestRegisterStrategy")
+	assert.Panics(t, func() { RegisterStrategy("TestRegisterStrategy", factory) })
+	assert.Panics(t, func() { RegisterStrategy("Nil", nil) })
+}
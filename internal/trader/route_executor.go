@@ -0,0 +1,206 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/models"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+// routeHop is a jump a route makes: a single order on a direct market, or a
+// sell into the bridge and a buy out of it.
+type routeHop struct {
+	From, To string
+	Legs     []graphEdge
+}
+
+func (h routeHop) isDirect() bool {
+	return len(h.Legs) == 1
+}
+
+// routeHops splits a route into the jumps it makes. The bridge is only passed
+// through, a sell into it and the buy out of it that follows are one jump, so
+// every hop ends in a coin and a settled hop never leaves funds in the bridge.
+func routeHops(route *tradeRoute, bridge string) ([]routeHop, error) {
+	var hops []routeHop
+	for i := 0; i < len(route.Legs); i++ {
+		leg := route.Legs[i]
+		if leg.To != bridge {
+			hops = append(hops, routeHop{From: leg.From, To: leg.To, Legs: []graphEdge{leg}})
+			continue
+		}
+		if i+1 == len(route.Legs) {
+			return nil, fmt.Errorf("route %s ends in the bridge", route)
+		}
+		next := route.Legs[i+1]
+		if leg.Symbol != leg.From+bridge || leg.Side != binance.OrderSideSell ||
+			next.Symbol != next.To+bridge || next.Side != binance.OrderSideBuy {
+			return nil, fmt.Errorf("route %s passes the bridge on a market not quoted in it", route)
+		}
+		hops = append(hops, routeHop{From: leg.From, To: next.To, Legs: []graphEdge{leg, next}})
+		i++
+	}
+	return hops, nil
+}
+
+// planHop sizes the jump of a hop that trades amount of hop.From at the given
+// quotes and checks its orders against the exchange filters.
+func planHop(ctx StrategyContext, hop routeHop, amount decimal.Decimal, quotes Quotes) (*jumpRoute, error) {
+	pair := &models.Pair{FromCoinSymbol: hop.From, ToCoinSymbol: hop.To}
+	if !hop.isDirect() {
+		return planBridgeRoute(ctx, pair, amount, quotes)
+	}
+	plan, err := planDirectRoute(ctx, pair, amount, quotes)
+	if err == nil && plan == nil {
+		err = fmt.Errorf("%s is not trading or not quoted", hop.Legs[0].Symbol)
+	}
+	return plan, err
+}
+
+// validateRoute checks every hop of a route against the exchange filters,
+// sizing each one from the estimated proceeds of the previous hop, so a route
+// is not started if one of its later orders would be rejected.
+func validateRoute(ctx StrategyContext, hops []routeHop, quantity decimal.Decimal, quotes Quotes) error {
+	amount := quantity
+	for i, hop := range hops {
+		plan, err := planHop(ctx, hop, amount, quotes)
+		if err != nil {
+			return fmt.Errorf("hop %d (%s>%s): %w", i+1, hop.From, hop.To, err)
+		}
+		amount = plan.Expected
+	}
+	return nil
+}
+
+// executeRoute trades quantity of the first coin of a route along its hops in
+// sequence. Every hop is recorded as a jump that shares the ID of the route,
+// so a hop interrupted by a crash or an order with an unknown outcome is
+// settled by RecoverJumps like any other jump. Each hop waits for its fill and
+// spends what the previous one actually produced, sized at fresh quotes. The
+// profit is recorded on the last one.
+// It returns the coin the account holds afterwards. When a hop fails with the
+// funds still in its from coin, the route stops there and that coin is
+// returned along with the error. When a hop is left in flight, in particular a
+// buy out of the bridge that is retried or rolled back by recovery on the next
+// tick, the returned coin is empty: the funds are not in any coin yet.
+func executeRoute(ctx StrategyContext, route *tradeRoute, quantity decimal.Decimal, profit float64, quotes Quotes) (string, error) {
+	l := ctx.Logger.With(zap.String("route", route.String()), zap.Stringer("quantity", quantity))
+	hops, err := routeHops(route, ctx.Cfg.Trading.Bridge)
+	if err != nil {
+		l.Error("Route cannot be traded as jumps, aborting.", zap.Error(err))
+		return route.From(), err
+	}
+	if err := validateRoute(ctx, hops, quantity, quotes); err != nil {
+		l.Error("Route violates the exchange filters, aborting.", zap.Error(err))
+		return route.From(), err
+	}
+	l.Info("Executing route...")
+
+	routeID := fmt.Sprintf("route-%d", ctx.clock().Now().UnixMilli())
+	amount := quantity
+	for i, hop := range hops {
+		if i > 0 {
+			fresh, err := fetchQuotes(ctx)
+			if err != nil {
+				return stopRoute(ctx, route, hop.From, err)
+			}
+			quotes = fresh
+		}
+		plan, err := planHop(ctx, hop, amount, quotes)
+		if err != nil {
+			return stopRoute(ctx, route, hop.From, fmt.Errorf("could not size hop %s>%s: %w", hop.From, hop.To, err))
+		}
+
+		hopProfit := 0.0
+		if i == len(hops)-1 {
+			hopProfit = profit
+		}
+		pair := &models.Pair{FromCoinSymbol: hop.From, ToCoinSymbol: hop.To}
+		jump, err := runJump(ctx, pair, plan, amount, hopProfit, routeID)
+		if err != nil {
+			if jump != nil && !jump.IsFinished() {
+				l.Error("Route hop is in flight, recovery will settle it", zap.Uint("jump_id", jump.ID), zap.Error(err))
+				return "", err
+			}
+			return stopRoute(ctx, route, hop.From, err)
+		}
+
+		amount = jump.BuyQuantity
+		l.Info("Route hop finished",
+			zap.Int("hop", i+1),
+			zap.Uint("jump_id", jump.ID),
+			zap.Stringer("received", amount),
+			zap.String("coin", hop.To))
+	}
+
+	finishRoute(ctx, route.From(), route.To())
+	l.Info("Route finished.", zap.String("new_coin", route.To()), zap.Stringer("received", amount))
+	return route.To(), nil
+}
+
+// stopRoute ends a route that failed with the funds held in a coin, and
+// continues from that coin. It returns the coin and the cause of the failure.
+func stopRoute(ctx StrategyContext, route *tradeRoute, held string, cause error) (string, error) {
+	if held == route.From() {
+		return held, cause
+	}
+	ctx.Logger.Error("Route failed, continuing from the coin it reached",
+		zap.String("route", route.String()),
+		zap.String("held_coin", held),
+		zap.Error(cause))
+	finishRoute(ctx, route.From(), held)
+	return held, cause
+}
+
+// finishRecoveredRoute ends the route of a hop settled by recovery. The route
+// does not go on from that hop, so the ratios are re-baselined around the coin
+// it left, the way stopRoute does for a route that stops part way.
+func finishRecoveredRoute(ctx StrategyContext, jump *models.Jump) {
+	var first models.Jump
+	if err := ctx.DB.Where("route_id = ?", jump.RouteID).Order("id").First(&first).Error; err != nil {
+		ctx.Logger.Error("Could not find the first hop of the route, ratios are not updated",
+			zap.String("route_id", jump.RouteID), zap.Error(err))
+		return
+	}
+	finishRoute(ctx, first.FromCoinSymbol, jump.HeldCoin())
+}
+
+// finishRoute re-baselines the ratios around the coin a route ended in, if it
+// is not the coin it started from, and refreshes the balances.
+func finishRoute(ctx StrategyContext, from, to string) {
+	if to != from {
+		prices, err := ctx.priceSource().GetAllTickerPrices(ctx.Context())
+		if err != nil {
+			ctx.Logger.Warn("Could not get prices to update ratios after route", zap.Error(err))
+		}
+		if coinPrice, err := parsePrice(prices, to+ctx.Cfg.Trading.Bridge); err != nil {
+			ctx.Logger.Error("Failed to update pair ratios, the new coin has no bridge price", zap.Error(err))
+			if err := setCurrentCoin(ctx, to); err != nil {
+				ctx.Logger.Error("Failed to record current coin", zap.Error(err))
+			}
+		} else {
+			rebaseline(ctx, to, coinPrice, prices)
+		}
+	}
+	syncPortfolio(ctx)
+}
+
+// routeProfit values a route against the benchmark of the jump it makes: a
+// round trip against holding the coin, and a route to another coin against
+// the ratio of the enabled pair between the two. The scout margin is deducted
+// like in calculateProfitForPair. It returns false if the route ends in a coin
+// there is no enabled pair to.
+func routeProfit(ctx StrategyContext, route *tradeRoute, pairs map[string]models.Pair) (decimal.Decimal, bool) {
+	one := decimal.NewFromInt(1)
+	margin := decimal.NewFromFloat(ctx.Cfg.Trading.ScoutMargin).Div(decimal.NewFromInt(100))
+	if route.To() == route.From() {
+		return route.Rate.Sub(one).Sub(margin), true
+	}
+	pair, ok := pairs[route.To()]
+	if !ok || !pair.Ratio.IsPositive() {
+		return decimal.Zero, false
+	}
+	return divRatio(route.Rate, pair.Ratio).Sub(one).Sub(margin), true
+}
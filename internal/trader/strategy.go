@@ -1,19 +1,44 @@
 package trader
 
 import (
+	"context"
+
 	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/clock"
 	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // StrategyContext provides the strategy with access to the core components.
 type StrategyContext struct {
+	Ctx           context.Context // Cancelled when the engine stops
 	Logger        *zap.Logger
 	Cfg           *config.Config
 	RestClient    binance.RestClientInterface
 	DB            *gorm.DB
-	ExchangeRules map[string]binance.SymbolInfo
+	ExchangeRules *ExchangeRules
+	Portfolio     *Portfolio
+	Prices        PriceSource // Defaults to RestClient when nil
+	Clock         clock.Clock // Defaults to the wall clock when nil
+}
+
+// Context returns the context API calls are made with, which is cancelled
+// when the engine stops.
+func (ctx StrategyContext) Context() context.Context {
+	if ctx.Ctx == nil {
+		return context.Background()
+	}
+	return ctx.Ctx
+}
+
+// clock returns the clock the strategy runs on.
+func (ctx StrategyContext) clock() clock.Clock {
+	if ctx.Clock == nil {
+		return clock.Real()
+	}
+	return ctx.Clock
 }
 
 // Strategy defines the interface for a trading strategy.
@@ -27,3 +52,10 @@ type Strategy interface {
 	// Scout is the main logic of the strategy, called periodically by the engine.
 	Scout(ctx StrategyContext) error
 }
+
+// JumpAware is implemented by strategies that need to know when the engine
+// settles a jump that was left unfinished by a crash or a failed leg.
+type JumpAware interface {
+	// OnJumpSettled is called with every recovered jump that reached a final state.
+	OnJumpSettled(jump models.Jump)
+}
@@ -1,24 +1,25 @@
 package trader
 
 import (
+	"binance-trade-bot-go/internal/binance"
 	"binance-trade-bot-go/internal/models"
+	"errors"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
-	"math"
-	"strconv"
 	"sync"
 )
 
 // tradeOpportunity holds the details of a profitable trade.
 type tradeOpportunity struct {
 	Pair   models.Pair
-	Profit float64
+	Profit decimal.Decimal
 }
 
 // findBestJump searches for the most profitable trade from a given source coin.
-func findBestJump(ctx StrategyContext, fromCoin *models.Coin, prices map[string]string) (*tradeOpportunity, error) {
+func findBestJump(ctx StrategyContext, fromCoin *models.Coin, quotes Quotes) (*tradeOpportunity, error) {
 	var pairs []models.Pair
-	if err := ctx.DB.Where("from_coin_symbol = ?", fromCoin.Symbol).Find(&pairs).Error; err != nil {
+	if err := ctx.DB.Where("from_coin_symbol = ? AND enabled = ?", fromCoin.Symbol, true).Find(&pairs).Error; err != nil {
 		return nil, fmt.Errorf("could not get pairs for coin %s: %w", fromCoin.Symbol, err)
 	}
 
@@ -33,13 +34,17 @@ func findBestJump(ctx StrategyContext, fromCoin *models.Coin, prices map[string]
 		wg.Add(1)
 		go func(pair models.Pair) {
 			defer wg.Done()
-			profit, err := calculateProfitForPair(ctx, &pair, prices)
+			profit, err := calculateProfitForPair(ctx, &pair, quotes)
+			if errors.Is(err, errPairNotTrading) {
+				ctx.Logger.Debug("Skipping pair", zap.String("pair", pair.FromCoinSymbol+"/"+pair.ToCoinSymbol), zap.Error(err))
+				return
+			}
 			if err != nil {
 				ctx.Logger.Warn("Failed to calculate profit for pair", zap.String("pair", pair.FromCoinSymbol+"/"+pair.ToCoinSymbol), zap.Error(err))
 				return
 			}
 
-			if profit > 0 {
+			if profit.IsPositive() {
 				opportunities <- tradeOpportunity{Pair: pair, Profit: profit}
 			}
 		}(p)
@@ -52,7 +57,7 @@ func findBestJump(ctx StrategyContext, fromCoin *models.Coin, prices map[string]
 
 	var bestOpp *tradeOpportunity
 	for opp := range opportunities {
-		if bestOpp == nil || opp.Profit > bestOpp.Profit {
+		if bestOpp == nil || opp.Profit.GreaterThan(bestOpp.Profit) {
 			currentOpp := opp
 			bestOpp = &currentOpp
 		}
@@ -62,195 +67,172 @@ func findBestJump(ctx StrategyContext, fromCoin *models.Coin, prices map[string]
 }
 
 // calculateProfitForPair is the core profit calculation logic.
-func calculateProfitForPair(ctx StrategyContext, pair *models.Pair, prices map[string]string) (float64, error) {
+// The from coin is sold at its bid and the to coin bought at its ask, so with
+// the bid/ask profit model the spread of both books counts against the jump.
+func calculateProfitForPair(ctx StrategyContext, pair *models.Pair, quotes Quotes) (decimal.Decimal, error) {
 	bridge := "USDT" // Default to USDT for now
 	if ctx.Cfg.Trading.Bridge != "" {
 		bridge = ctx.Cfg.Trading.Bridge
 	}
 
 	if pair.ToCoinSymbol == bridge {
-		return 0, nil
+		return decimal.Zero, nil
+	}
+
+	// The pair may still be enabled if a symbol stopped trading since the last
+	// pair status update.
+	if ctx.ExchangeRules.Len() > 0 {
+		if reason := pairExclusion(ctx, pair); reason != "" {
+			return decimal.Zero, fmt.Errorf("%w: %s", errPairNotTrading, reason)
+		}
 	}
 
-	feeRate := ctx.Cfg.Trading.FeeRate
-	margin := ctx.Cfg.Trading.ScoutMargin / 100
+	margin := decimal.NewFromFloat(ctx.Cfg.Trading.ScoutMargin).Div(decimal.NewFromInt(100))
 
 	fromSymbol := pair.FromCoinSymbol + bridge
 	toSymbol := pair.ToCoinSymbol + bridge
 
-	fromPriceStr, fromOk := prices[fromSymbol]
-	toPriceStr, toOk := prices[toSymbol]
-
-	if !fromOk || !toOk {
-		return 0, fmt.Errorf("prices not available for pair %s/%s", fromSymbol, toSymbol)
+	fromPrice, err := quotes.sellPrice(fromSymbol)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("prices not available for pair %s/%s: %w", fromSymbol, toSymbol, err)
 	}
-
-	fromPrice, err1 := strconv.ParseFloat(fromPriceStr, 64)
-	toPrice, err2 := strconv.ParseFloat(toPriceStr, 64)
-
-	if err1 != nil || err2 != nil {
-		return 0, fmt.Errorf("failed to parse prices for pair %s/%s", fromSymbol, toSymbol)
+	toPrice, err := quotes.buyPrice(toSymbol)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("prices not available for pair %s/%s: %w", fromSymbol, toSymbol, err)
 	}
 
-	if fromPrice == 0 || toPrice == 0 {
-		return 0, fmt.Errorf("invalid prices for pair %s/%s", fromSymbol, toSymbol)
+	if !pair.Ratio.IsPositive() {
+		return decimal.Zero, fmt.Errorf("pair %s/%s has no ratio to compare against", pair.FromCoinSymbol, pair.ToCoinSymbol)
 	}
-
-	currentRatio := fromPrice / toPrice
-	effectiveRatio := currentRatio * (1 - feeRate) * (1 - feeRate)
-	profit := (effectiveRatio / pair.Ratio) - 1 - margin
+	currentRatio := divRatio(fromPrice, toPrice)
+	effectiveRatio := currentRatio.Mul(afterFee(ctx)).Mul(afterFee(ctx))
+	profit := divRatio(effectiveRatio, pair.Ratio).Sub(decimal.NewFromInt(1)).Sub(margin)
 
 	return profit, nil
 }
 
-// formatQuantity formats a quantity according to the symbol's LOT_SIZE filter rules.
-func formatQuantity(ctx StrategyContext, symbol string, quantity float64) (float64, error) {
-	rule, ok := ctx.ExchangeRules[symbol]
+// formatQuantity floors a quantity to the step size of the symbol's LOT_SIZE
+// filter. The rounding is exact, so the result always lands on a step.
+func formatQuantity(ctx StrategyContext, symbol string, quantity decimal.Decimal) (decimal.Decimal, error) {
+	rule, ok := ctx.ExchangeRules.Symbol(symbol)
 	if !ok {
 		ctx.Logger.Warn("No exchange rule found for symbol, using default formatting", zap.String("symbol", symbol))
 		return quantity, nil
 	}
 
-	var stepSize, minQtyStr string
-	for _, filter := range rule.Filters {
-		if filter.FilterType == "LOT_SIZE" {
-			stepSize = filter.StepSize
-			minQtyStr = filter.MinQty
-			break
-		}
+	filters, err := rule.ParseFilters()
+	if err != nil {
+		return decimal.Zero, err
 	}
-
-	if stepSize == "" {
+	lotSize := filters.LotSize
+	if !lotSize.StepSize.IsPositive() {
 		ctx.Logger.Warn("LOT_SIZE filter not found, using default formatting", zap.String("symbol", symbol))
 		return quantity, nil
 	}
 
-	minQty, _ := strconv.ParseFloat(minQtyStr, 64)
-	if quantity < minQty {
-		return 0, fmt.Errorf("quantity %.8f is less than minQty %.8f for symbol %s", quantity, minQty, symbol)
+	if quantity.LessThan(lotSize.MinQty) {
+		return decimal.Zero, &binance.FilterError{Symbol: symbol, Filter: binance.FilterTypeLotSize,
+			Reason: fmt.Sprintf("quantity %s is below the minimum of %s", quantity, lotSize.MinQty)}
 	}
 
-	var precision int
-	dotIndex := -1
-	for i, r := range stepSize {
-		if r == '.' {
-			dotIndex = i
-			break
-		}
-	}
-
-	if dotIndex != -1 {
-		trimmed := ""
-		for i := len(stepSize) - 1; i > dotIndex; i-- {
-			if stepSize[i] != '0' {
-				trimmed = stepSize[0 : i+1]
-				break
-			}
-		}
-		if trimmed != "" {
-			precision = len(trimmed) - dotIndex - 1
-		}
-	}
-
-	multiplier := math.Pow(10, float64(precision))
-	floored := math.Floor(quantity*multiplier) / multiplier
-
-	if floored < minQty {
-		return 0, fmt.Errorf("formatted quantity %.8f is less than minQty %.8f for symbol %s", floored, minQty, symbol)
+	floored := lotSize.RoundDown(quantity)
+	if floored.LessThan(lotSize.MinQty) {
+		return decimal.Zero, &binance.FilterError{Symbol: symbol, Filter: binance.FilterTypeLotSize,
+			Reason: fmt.Sprintf("formatted quantity %s is below the minimum of %s", floored, lotSize.MinQty)}
 	}
 
 	return floored, nil
 }
 
-// ExecuteJump performs a two-step trade and records it in the database.
-func ExecuteJump(ctx StrategyContext, pair *models.Pair, fromCoinQuantity float64, profit float64) error {
-	bridge := ctx.Cfg.Trading.Bridge
-	fromCoin := pair.FromCoinSymbol
-	toCoin := pair.ToCoinSymbol
-
+// ExecuteJump trades the from coin of a pair into its to coin and records it
+// in the database. The jump goes through the bridge in two steps, or in a
+// single order when a direct market between the coins yields more of the to
+// coin after fees (see planJump).
+// Progress is persisted as a models.Jump before every exchange call, so a jump
+// that fails or is interrupted between the legs can be settled by RecoverJumps.
+// Each leg waits for the exchange to report the order as filled, and the buy
+// leg is sized from the bridge amount the sell actually produced. Every order
+// is checked against the exchange filters at the scouted quotes before anything
+// is sent.
+func ExecuteJump(ctx StrategyContext, pair *models.Pair, fromCoinQuantity decimal.Decimal, profit float64, quotes Quotes) error {
 	l := ctx.Logger.With(
-		zap.String("from_coin", fromCoin),
-		zap.String("to_coin", toCoin),
-		zap.Float64("quantity", fromCoinQuantity),
+		zap.String("from_coin", pair.FromCoinSymbol),
+		zap.String("to_coin", pair.ToCoinSymbol),
+		zap.Stringer("quantity", fromCoinQuantity),
 	)
 	l.Info("Executing jump transaction...")
 
-	// --- Step 1: Sell FromCoin for Bridge Coin ---
-	sellSymbol := fromCoin + bridge
-	formattedSellQty, err := formatQuantity(ctx, sellSymbol, fromCoinQuantity)
+	route, err := planJump(ctx, pair, fromCoinQuantity, quotes)
 	if err != nil {
-		l.Error("Failed to format sell quantity, aborting jump.", zap.Error(err))
+		l.Error("Jump violates the exchange filters, aborting jump.", zap.Error(err))
 		return err
 	}
+	_, err = runJump(ctx, pair, route, fromCoinQuantity, profit, "")
+	return err
+}
 
-	sellOrder, err := ctx.RestClient.CreateOrder(sellSymbol, "SELL", formattedSellQty)
-	if err != nil {
-		return fmt.Errorf("failed to execute sell order for %s: %w", sellSymbol, err)
-	}
-	// In a real scenario, we'd wait for the order to fill. For now, we simulate it.
-	prices, _ := ctx.RestClient.GetAllTickerPrices()
-	price, _ := strconv.ParseFloat(prices[sellSymbol], 64)
-	bridgeQtyObtained := formattedSellQty * price * (1 - ctx.Cfg.Trading.FeeRate)
-	l.Info("Sell order created", zap.Int64("orderId", sellOrder.OrderID))
-
-	// Record the SELL trade
-	sellTrade := models.Trade{
-		Symbol:        sellSymbol,
-		Type:          "SELL",
-		Price:         price,
-		Quantity:      formattedSellQty,
-		QuoteQuantity: formattedSellQty * price,
-		Timestamp:     sellOrder.TransactTime,
-		// IsSimulation:  ctx.Cfg.Trading.IsSimulation,
+// runJump records a jump along a planned route and executes its legs. A hop of
+// a multi-hop route passes the ID of the route. Once the jump is recorded it is
+// returned along with any error, in whichever state it was left.
+func runJump(ctx StrategyContext, pair *models.Pair, route *jumpRoute, fromCoinQuantity decimal.Decimal, profit float64, routeID string) (*models.Jump, error) {
+	l := ctx.Logger.With(zap.String("from_coin", pair.FromCoinSymbol), zap.String("to_coin", pair.ToCoinSymbol))
+
+	jump := &models.Jump{
+		FromCoinSymbol: pair.FromCoinSymbol,
+		ToCoinSymbol:   pair.ToCoinSymbol,
+		BridgeSymbol:   ctx.Cfg.Trading.Bridge,
+		State:          models.JumpStatePendingSell,
+		RouteID:        routeID,
+		FromQuantity:   route.Quantity,
+		Profit:         profit,
+	}
+	if route.isDirect() {
+		jump.DirectSymbol = route.DirectSymbol
+		if route.Side == binance.OrderSideBuy {
+			// The order is sized in the to coin, the from coin is what it spends.
+			jump.FromQuantity = fromCoinQuantity
+		}
 	}
-	if err := ctx.DB.Create(&sellTrade).Error; err != nil {
-		l.Error("Failed to record sell trade", zap.Error(err))
-		// Continue even if recording fails, as the trade itself succeeded.
+	if err := ctx.DB.Create(jump).Error; err != nil {
+		return nil, fmt.Errorf("could not record jump, aborting: %w", err)
 	}
 
-	// --- Step 2: Buy ToCoin with Bridge Coin ---
-	buySymbol := toCoin + bridge
-	prices, _ = ctx.RestClient.GetAllTickerPrices()
-	toPrice, _ := strconv.ParseFloat(prices[buySymbol], 64)
-	if toPrice == 0 {
-		l.Error("Could not get price for ToCoin, aborting jump", zap.String("symbol", buySymbol))
-		return fmt.Errorf("could not get price for %s", buySymbol)
-	}
-	buyQuantity := bridgeQtyObtained / toPrice
-	formattedBuyQty, err := formatQuantity(ctx, buySymbol, buyQuantity)
-	if err != nil {
-		l.Error("Failed to format buy quantity, aborting jump.", zap.Error(err))
-		return err
+	if route.isDirect() {
+		l.Info("Jumping on the direct market", zap.String("symbol", route.DirectSymbol), zap.String("side", route.Side))
+		return jump, executeDirectLeg(ctx, jump, route.Quantity)
 	}
 
-	formattedBuyQty, err = formatQuantity(ctx, buySymbol, buyQuantity)
-	if err != nil {
-		l.Error("Failed to format buy quantity, aborting jump.", zap.Error(err))
-		return err
-	}
-	buyOrder, err := ctx.RestClient.CreateOrder(buySymbol, "BUY", formattedBuyQty)
-	if err != nil {
-		return fmt.Errorf("failed to execute buy order for %s: %w", buySymbol, err)
-	}
-	l.Info("Buy order created", zap.Int64("orderId", buyOrder.OrderID))
-
-	// Record the BUY trade with profit
-	buyTrade := models.Trade{
-		Symbol:        buySymbol,
-		Type:          "BUY",
-		Price:         toPrice,
-		Quantity:      formattedBuyQty,
-		QuoteQuantity: formattedBuyQty * toPrice,
-		Timestamp:     buyOrder.TransactTime,
-		// IsSimulation:  ctx.Cfg.Trading.IsSimulation,
-		Profit: profit, // Store the overall profit in the final leg of the jump
+	// --- Step 1: Sell FromCoin for Bridge Coin ---
+	if err := executeSellLeg(ctx, jump); err != nil {
+		return jump, err
 	}
-	if err := ctx.DB.Create(&buyTrade).Error; err != nil {
-		l.Error("Failed to record buy trade", zap.Error(err))
-		// Continue even if recording fails
+
+	// --- Step 2: Buy ToCoin with Bridge Coin ---
+	if err := executeBuyLeg(ctx, jump, pair.ToCoinSymbol); err != nil {
+		l.Error("Buy leg failed, jump will be resumed or rolled back", zap.Uint("jump_id", jump.ID), zap.Error(err))
+		return jump, err
 	}
 
-	l.Info("Jump transaction successful.", zap.String("new_coin", toCoin))
+	return jump, nil
+}
 
-	return nil
+// recordTrade stores a reconciled fill as a trade. A failure is only logged,
+// since the trade itself already happened on the exchange.
+func recordTrade(ctx StrategyContext, fill *orderFill, profit float64) {
+	trade := models.Trade{
+		Symbol:          fill.Symbol,
+		Type:            fill.Side,
+		OrderID:         fill.OrderID,
+		Price:           fill.Price,
+		Quantity:        fill.Quantity,
+		QuoteQuantity:   fill.QuoteQuantity,
+		Commission:      fill.Commission,
+		CommissionAsset: fill.CommissionAsset,
+		Timestamp:       fill.TransactTime,
+		IsSimulation:    ctx.Cfg.Trading.DryRun,
+		Profit:          profit,
+	}
+	if err := ctx.DB.Create(&trade).Error; err != nil {
+		ctx.Logger.Error("Failed to record trade", zap.String("symbol", fill.Symbol), zap.String("side", fill.Side), zap.Error(err))
+	}
 }
@@ -5,8 +5,11 @@ import (
 	"binance-trade-bot-go/internal/config"
 	"binance-trade-bot-go/internal/models"
 	"encoding/json"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"math/rand"
 	"testing"
 )
 
@@ -33,7 +36,7 @@ func TestCalculateProfitForPair(t *testing.T) {
 	}{
 		{
 			name: "Profitable Jump",
-			pair: models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 15.0}, // Old ratio was 15
+			pair: models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("15")}, // Old ratio was 15
 			prices: map[string]string{
 				"BTCUSDT": "30000",
 				"ETHUSDT": "1800", // New ratio is 30000/1800 = 16.67
@@ -44,7 +47,7 @@ func TestCalculateProfitForPair(t *testing.T) {
 		},
 		{
 			name: "Unprofitable Jump",
-			pair: models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: 17.0}, // Old ratio was 17
+			pair: models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH", Ratio: dec("17")}, // Old ratio was 17
 			prices: map[string]string{
 				"BTCUSDT": "30000",
 				"ETHUSDT": "1800", // New ratio is 16.67
@@ -82,14 +85,13 @@ func TestCalculateProfitForPair(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			profit, err := calculateProfitForPair(mockCtx, &tc.pair, tc.prices)
+			profit, err := calculateProfitForPair(mockCtx, &tc.pair, quotesFromPrices(tc.prices))
 
 			if tc.expectError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				// Use a small tolerance for float comparison
-				assert.InDelta(t, tc.expectedProfit, profit, 0.001)
+				assert.InDelta(t, tc.expectedProfit, profit.InexactFloat64(), 0.001)
 			}
 		})
 	}
@@ -115,55 +117,95 @@ func TestFormatQuantity(t *testing.T) {
 
 	mockCtx := StrategyContext{
 		Logger:        zap.NewNop(),
-		ExchangeRules: exchangeRules,
+		ExchangeRules: NewExchangeRules(exchangeRules),
 	}
 
 	testCases := []struct {
 		name        string
 		symbol      string
-		quantity    float64
-		expectedQty float64
+		quantity    string
+		expectedQty string
 		expectError bool
 	}{
 		{
 			name:        "Quantity needs flooring",
 			symbol:      "BTCUSDT",
-			quantity:    1.23456789,
-			expectedQty: 1.23456,
+			quantity:    "1.23456789",
+			expectedQty: "1.23456",
 			expectError: false,
 		},
 		{
 			name:        "Quantity is already correct",
 			symbol:      "BTCUSDT",
-			quantity:    1.23456,
-			expectedQty: 1.23456,
+			quantity:    "1.23456",
+			expectedQty: "1.23456",
+			expectError: false,
+		},
+		{
+			// math.Floor(0.29*1e5)/1e5 is 0.28999 in float64
+			name:        "Quantity on a step is not rounded down",
+			symbol:      "BTCUSDT",
+			quantity:    "0.29",
+			expectedQty: "0.29",
 			expectError: false,
 		},
 		{
 			name:        "Quantity is below MinQty",
 			symbol:      "BTCUSDT",
-			quantity:    0.000001,
+			quantity:    "0.000001",
 			expectError: true,
 		},
 		{
 			name:        "Symbol has no specific rule",
 			symbol:      "ETHUSDT", // This rule doesn't exist in our mock
-			quantity:    1.23456789,
-			expectedQty: 1.23456789, // Should return original value
+			quantity:    "1.23456789",
+			expectedQty: "1.23456789", // Should return original value
 			expectError: false,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			formattedQty, err := formatQuantity(mockCtx, tc.symbol, tc.quantity)
+			formattedQty, err := formatQuantity(mockCtx, tc.symbol, dec(tc.quantity))
 
 			if tc.expectError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tc.expectedQty, formattedQty)
+				assert.Equal(t, tc.expectedQty, formattedQty.String())
 			}
 		})
 	}
 }
+
+// TestFormatQuantity_LandsOnStep checks that quantities derived from random
+// amounts and prices, the way the buy leg is sized, always format to an exact
+// multiple of the step size that passes the LOT_SIZE filter.
+func TestFormatQuantity_LandsOnStep(t *testing.T) {
+	steps := []string{"1", "0.1", "0.01", "0.05", "0.001", "0.00001", "0.00000001"}
+	rng := rand.New(rand.NewSource(1))
+
+	for _, step := range steps {
+		rule := binance.SymbolInfo{Filters: []binance.Filter{{FilterType: binance.FilterTypeLotSize, StepSize: step, MinQty: step}}}
+		ctx := StrategyContext{Logger: zap.NewNop(), ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{"ETHUSDT": rule})}
+		filters, err := rule.ParseFilters()
+		require.NoError(t, err)
+
+		for i := 0; i < 2000; i++ {
+			bridge := decimal.New(rng.Int63n(1e10)+1, -int32(rng.Intn(9)))
+			price := decimal.New(rng.Int63n(1e8)+1, -int32(rng.Intn(9)))
+			quantity := bridge.Div(price)
+
+			formatted, err := formatQuantity(ctx, "ETHUSDT", quantity)
+			if err != nil {
+				// Only amounts below one step may be rejected.
+				require.True(t, quantity.LessThan(filters.LotSize.StepSize), "step %s, quantity %s: %v", step, quantity, err)
+				continue
+			}
+			require.True(t, formatted.Mod(filters.LotSize.StepSize).IsZero(), "step %s, quantity %s formatted to %s", step, quantity, formatted)
+			require.True(t, formatted.LessThanOrEqual(quantity))
+			require.True(t, quantity.Sub(formatted).LessThan(filters.LotSize.StepSize))
+			require.NoError(t, filters.ValidateMarketOrder("ETHUSDT", formatted, price))
+		}
+	}
+}
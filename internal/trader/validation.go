@@ -0,0 +1,60 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/models"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// validateOrder checks a market order against the exchange filters of its
+// symbol. Symbols without exchange rules are not checked.
+func validateOrder(ctx StrategyContext, symbol string, quantity, price decimal.Decimal) error {
+	rule, ok := ctx.ExchangeRules.Symbol(symbol)
+	if !ok {
+		return nil
+	}
+	filters, err := rule.ParseFilters()
+	if err != nil {
+		return err
+	}
+	return filters.ValidateMarketOrder(symbol, quantity, price)
+}
+
+// validateJump checks both legs of a jump through the bridge against the
+// exchange filters before anything is sent, so a jump is never left stranded
+// in the bridge because the exchange rejects its buy. The buy leg is estimated
+// from the quotes and the fee rate, the way calculateProfitForPair values it,
+// and the estimated net quantity of the to coin is returned.
+func validateJump(ctx StrategyContext, pair *models.Pair, sellQuantity decimal.Decimal, quotes Quotes) (decimal.Decimal, error) {
+	bridge := ctx.Cfg.Trading.Bridge
+	sellSymbol := pair.FromCoinSymbol + bridge
+	buySymbol := pair.ToCoinSymbol + bridge
+
+	sellPrice, err := quotes.sellPrice(sellSymbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if err := validateOrder(ctx, sellSymbol, sellQuantity, sellPrice); err != nil {
+		return decimal.Zero, fmt.Errorf("sell leg: %w", err)
+	}
+
+	buyPrice, err := quotes.buyPrice(buySymbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	bridgeQuantity := sellQuantity.Mul(sellPrice).Mul(afterFee(ctx))
+	buyQuantity, err := formatQuantity(ctx, buySymbol, bridgeQuantity.Div(buyPrice))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("buy leg: %w", err)
+	}
+	if err := validateOrder(ctx, buySymbol, buyQuantity, buyPrice); err != nil {
+		return decimal.Zero, fmt.Errorf("buy leg: %w", err)
+	}
+	return buyQuantity.Mul(afterFee(ctx)), nil
+}
+
+// afterFee returns the share of an order's proceeds left after the trading fee.
+func afterFee(ctx StrategyContext) decimal.Decimal {
+	return decimal.NewFromInt(1).Sub(decimal.NewFromFloat(ctx.Cfg.Trading.FeeRate))
+}
@@ -0,0 +1,71 @@
+package trader
+
+import (
+	"binance-trade-bot-go/internal/binance"
+	"binance-trade-bot-go/internal/config"
+	"binance-trade-bot-go/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidateJump(t *testing.T) {
+	ctx := StrategyContext{
+		Logger: zap.NewNop(),
+		Cfg:    &config.Config{Trading: config.Trading{Bridge: "USDT", FeeRate: 0.001}},
+		ExchangeRules: NewExchangeRules(map[string]binance.SymbolInfo{
+			"BTCUSDT": {Filters: []binance.Filter{
+				{FilterType: binance.FilterTypeLotSize, StepSize: "0.00001", MinQty: "0.00001"},
+				{FilterType: binance.FilterTypeMarketLotSize, MaxQty: "50"},
+				{FilterType: binance.FilterTypeNotional, MinNotional: "5", ApplyMinToMarket: true},
+			}},
+			"ETHUSDT": {Filters: []binance.Filter{
+				{FilterType: binance.FilterTypeLotSize, StepSize: "0.01", MinQty: "0.01"},
+				{FilterType: binance.FilterTypeMinNotional, MinNotional: "50", ApplyToMarket: true},
+			}},
+		}),
+	}
+	pair := &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}
+
+	testCases := []struct {
+		name     string
+		quantity string
+		err      string
+	}{
+		{name: "Both legs pass", quantity: "0.01"},
+		{name: "Sell above MARKET_LOT_SIZE", quantity: "60", err: "sell leg: MARKET_LOT_SIZE filter failure for BTCUSDT"},
+		{name: "Sell below NOTIONAL", quantity: "0.00005", err: "sell leg: NOTIONAL filter failure for BTCUSDT"},
+		// 0.0008 BTC sells for ~48 USDT, which buys 0.01 ETH worth 39 USDT.
+		{name: "Buy below MIN_NOTIONAL", quantity: "0.0008", err: "buy leg: MIN_NOTIONAL filter failure for ETHUSDT"},
+		// 0.0006 BTC sells for ~36 USDT, less than the smallest ETH lot.
+		{name: "Buy below LOT_SIZE", quantity: "0.0006", err: "buy leg: LOT_SIZE filter failure for ETHUSDT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validateJump(ctx, pair, dec(tc.quantity), testQuotes)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestExecuteJump_InvalidJumpSendsNothing(t *testing.T) {
+	ctx, mockClient := newJumpTestContext(t)
+	ctx.ExchangeRules = NewExchangeRules(map[string]binance.SymbolInfo{
+		"ETHUSDT": {Filters: []binance.Filter{{FilterType: binance.FilterTypeNotional, MinNotional: "100000", ApplyMinToMarket: true}}},
+	})
+
+	err := ExecuteJump(ctx, &models.Pair{FromCoinSymbol: "BTC", ToCoinSymbol: "ETH"}, dec("1"), 0.01, testQuotes)
+
+	assert.ErrorIs(t, err, binance.ErrFilterFailure)
+	mockClient.AssertNotCalled(t, "CreateOrder")
+	var jumps int64
+	require.NoError(t, ctx.DB.Model(&models.Jump{}).Count(&jumps).Error)
+	assert.Zero(t, jumps)
+}
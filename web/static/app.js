@@ -86,9 +86,10 @@ document.addEventListener('DOMContentLoaded', function() {
             
             const tradeTime = new Date(trade.Timestamp * 1000).toLocaleString();
             const sideClass = trade.Type.toLowerCase(); // 'buy' or 'sell'
-            const price = trade.Price.toFixed(4);
-            const quantity = trade.Quantity.toFixed(6);
-            const total = trade.QuoteQuantity.toFixed(4);
+            // Amounts are exact decimals, serialized as strings
+            const price = Number(trade.price).toFixed(4);
+            const quantity = Number(trade.quantity).toFixed(6);
+            const total = Number(trade.quote_quantity).toFixed(4);
             const profit = trade.profit ? trade.profit.toFixed(4) : 'N/A';
             const simulation = trade.IsSimulation ? 'Yes' : 'No';
 